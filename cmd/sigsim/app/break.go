@@ -0,0 +1,231 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/kommendorkapten/sigsim/pkg/ec"
+	"github.com/kommendorkapten/sigsim/pkg/ecdsa"
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+// cycles are the cycle detection methods which can be selected.
+var cycles = map[string]ec.Cycle{
+	"floyd":         ec.CycleFloyd,
+	"brent":         ec.CycleBrent,
+	"distinguished": ec.CycleDistinguished,
+}
+
+// BreakKey returns a command which generates a key on a curve and
+// recovers the private key from the public key.
+func BreakKey() *ffcli.Command {
+	var (
+		flagset = flag.NewFlagSet("sigsim break", flag.ExitOnError)
+		name    = flagset.String("c", "demo25",
+			"Named curve, one of "+strings.Join(curveNames(), ", "))
+		cycle = flagset.String("cycle", "floyd",
+			"Cycle detection, one of floyd, brent, distinguished")
+		bits = flagset.Int("bits", 0,
+			"Trailing zero bits of distinguished points")
+		timeout = flagset.Duration("t", 0, "Give up after this long")
+		method  = flagset.String("m", "rho",
+			"Method, one of rho, bsgs, ph or both to compare rho "+
+				"and bsgs")
+		par = flagset.Bool("parallel", false,
+			"Use the parallel collision search")
+		workers = flagset.Int("w", 0,
+			"Number of goroutines for the parallel search")
+		listen = flagset.String("listen", "",
+			"Accept remote workers on network:address, "+
+				"e.g tcp:127.0.0.1:4000 or unix:/tmp/rho.sock")
+	)
+
+	return &ffcli.Command{
+		Name:       "break",
+		ShortUsage: "sigsim break [-c name] [-m method]",
+		ShortHelp:  "Recover a private key from its public key",
+		LongHelp: "Generate a random key on the curve, and recover the " +
+			"private key from the public key with Pollard's rho, " +
+			"baby-step giant-step or Pohlig-Hellman.",
+		FlagSet: flagset,
+		Exec: func(ctx context.Context, args []string) error {
+			var f, ok = demoCurves[*name]
+			if !ok {
+				return fmt.Errorf("unknown curve %s", *name)
+			}
+
+			var c, err = f()
+			if err != nil {
+				return err
+			}
+
+			var cfg = ec.RhoConfig{
+				DistinguishedBits: *bits,
+				Workers:           *workers,
+			}
+			if cfg.Cycle, ok = cycles[*cycle]; !ok {
+				return fmt.Errorf("unknown cycle detection %s", *cycle)
+			}
+
+			if *listen != "" {
+				var network, addr, err = splitAddr(*listen)
+				if err != nil {
+					return err
+				}
+				cfg.Listener, err = net.Listen(network, addr)
+				if err != nil {
+					return fmt.Errorf("failed to listen: %w", err)
+				}
+				// The parallel search is needed for remote workers
+				*par = true
+			}
+
+			if *timeout > 0 {
+				var cancel context.CancelFunc
+
+				ctx, cancel = context.WithTimeout(ctx, *timeout)
+				defer cancel()
+			}
+
+			return BreakKeyCmd(ctx, c, &cfg, *par, *method)
+		},
+	}
+}
+
+// solver is a named discrete logarithm solver.
+type solver struct {
+	name  string
+	solve func() (int64, error)
+}
+
+// BreakKeyCmd generates a key on c and recovers it from the public key.
+// method is rho, bsgs, ph for Pohlig-Hellman, or both which runs rho and
+// bsgs to compare them.
+// If par is set, rho uses the parallel collision search.
+func BreakKeyCmd(ctx context.Context, c *ec.Curve, cfg *ec.RhoConfig,
+	par bool, method string) error {
+	var p, err = ecdsa.GenerateKey(c, rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	cfg.Progress = func(p ec.RhoProgress) {
+		SafePrintf("%d steps, %d restarts, %s\n", p.Steps, p.Restarts,
+			p.Elapsed.Round(time.Millisecond))
+	}
+
+	var rho = solver{
+		name: cfg.Cycle.String(),
+		solve: func() (int64, error) {
+			return c.Rho(ctx, c.G, p.Pub.P, c.N, cfg)
+		},
+	}
+	if par {
+		rho = solver{
+			name: "parallel collision search",
+			solve: func() (int64, error) {
+				return c.ParallelRho(ctx, c.G, p.Pub.P, c.N, cfg)
+			},
+		}
+	}
+	var bsgs = solver{
+		name: "baby-step giant-step",
+		solve: func() (int64, error) {
+			return c.SolveDLP(ctx, c.G, p.Pub.P, c.N)
+		},
+	}
+
+	var ph = solver{
+		name: "pohlig-hellman",
+		solve: func() (int64, error) {
+			return c.PohligHellman(ctx, c.G, p.Pub.P, c.N)
+		},
+	}
+
+	var solvers []solver
+	switch method {
+	case "rho":
+		solvers = []solver{rho}
+	case "bsgs":
+		solvers = []solver{bsgs}
+	case "ph":
+		solvers = []solver{ph}
+	case "both":
+		solvers = []solver{rho, bsgs}
+	default:
+		return fmt.Errorf("unknown method %s", method)
+	}
+
+	SafePrintf("Public key %+v on curve %s\n", p.Pub.P, c)
+
+	for _, s := range solvers {
+		var start = time.Now()
+		var d int64
+
+		if d, err = s.solve(); err != nil {
+			return fmt.Errorf("failed to recover key: %w", err)
+		}
+
+		SafePrintf("Recovered d = %d in %s using %s\n", d,
+			time.Since(start), s.name)
+		if d != p.D {
+			return fmt.Errorf("recovered key %d does not match %d", d,
+				p.D)
+		}
+	}
+
+	return nil
+}
+
+// RhoWorker returns a command which joins a parallel collision search
+// started with break -listen.
+func RhoWorker() *ffcli.Command {
+	var (
+		flagset = flag.NewFlagSet("sigsim worker", flag.ExitOnError)
+		connect = flagset.String("connect", "tcp:127.0.0.1:4000",
+			"Collector to connect to, network:address")
+		workers = flagset.Int("w", int(ec.Parallel),
+			"Number of goroutines")
+	)
+
+	return &ffcli.Command{
+		Name:       "worker",
+		ShortUsage: "sigsim worker [-connect network:address]",
+		ShortHelp:  "Join a parallel rho search",
+		LongHelp: "Connect to a collector started with break -listen, " +
+			"and search for distinguished points until the key is " +
+			"found.",
+		FlagSet: flagset,
+		Exec: func(ctx context.Context, args []string) error {
+			var network, addr, err = splitAddr(*connect)
+			if err != nil {
+				return err
+			}
+
+			var conn net.Conn
+			if conn, err = net.Dial(network, addr); err != nil {
+				return fmt.Errorf("failed to connect: %w", err)
+			}
+
+			SafePrintf("Connected to %s\n", *connect)
+
+			return ec.RhoWorker(ctx, conn, *workers)
+		},
+	}
+}
+
+// splitAddr splits network:address.
+func splitAddr(s string) (string, string, error) {
+	var network, addr, ok = strings.Cut(s, ":")
+	if !ok {
+		return "", "", fmt.Errorf("invalid address %s, expected "+
+			"network:address", s)
+	}
+
+	return network, addr, nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/kommendorkapten/sigsim/pkg/ec"
 	"github.com/kommendorkapten/sigsim/pkg/field"
+	smath "github.com/kommendorkapten/sigsim/pkg/math"
 	"github.com/peterbourgon/ff/v3/ffcli"
 )
 
@@ -26,6 +27,9 @@ func GenCurve() *ffcli.Command {
 		a       = flagset.Int("a", -3, "A parameter for curve")
 		b       = flagset.Int("b", 3411011, "B parameter for curve")
 		o       = flagset.Int("o", 0, "Offset to start search in")
+		smooth  = flagset.Int("smooth", 0,
+			"Search for an order with no prime factor above this, "+
+				"instead of a prime order. Such curves are insecure")
 	)
 
 	return &ffcli.Command{
@@ -40,12 +44,18 @@ func GenCurve() *ffcli.Command {
 				int64(*a),
 				int64(*b),
 				int64(*o),
+				int64(*smooth),
 			)
 		},
 	}
 }
 
-func GenCurveCmd(_ context.Context, n, a, b, o int64) error {
+// GenCurveCmd searches for a curve of prime order, by incrementing b.
+// If smooth is set, a curve whose order has no prime factor larger than
+// smooth is searched for instead, and a generator of maximal order is
+// picked. The discrete logarithm on such a curve is easily solved with
+// the Pohlig-Hellman algorithm.
+func GenCurveCmd(_ context.Context, n, a, b, o, smooth int64) error {
 	// we want to have a curve that satisfies:
 	// 1. order is a prime
 	// 2. Generator point that gives a cofactor of 1
@@ -70,9 +80,28 @@ func GenCurveCmd(_ context.Context, n, a, b, o int64) error {
 		fmt.Printf("Test curve %+v\n", c)
 
 		// This call takes a long time!
-		var order = c.CountPoints()
+		var order int64
+		if order, err = c.CountPoints(); err != nil {
+			return err
+		}
 		fmt.Printf("curve has order %d\n", order)
 
+		if smooth > 0 {
+			var pfs = smath.PrimeFactors(order)
+			// A curve with only the identity has no factors,
+			// and is of no use
+			if len(pfs) > 0 && pfs[len(pfs)-1] <= smooth {
+				fmt.Printf("Curve order is %d-smooth: %v\n", smooth, pfs)
+				c.G, c.N = maxOrderPoint(c, order)
+				fmt.Printf("Generator %+v with order %d\n", c.G, c.N)
+				fmt.Printf("Curve: %s\n", c)
+				break
+			}
+			fmt.Printf("Curve order is NOT %d-smooth\n", smooth)
+			b += 2
+			continue
+		}
+
 		var p = big.NewInt(order)
 		var prime = p.ProbablyPrime(256)
 		if prime {
@@ -84,9 +113,6 @@ func GenCurveCmd(_ context.Context, n, a, b, o int64) error {
 		b += 2
 	}
 
-
-
-
 	// var point = c.RandomPoint()
 	// var sgOrder = c.OrderBG(point)
 	// fmt.Printf("Order of sug-group %d\n", sgOrder)
@@ -98,10 +124,35 @@ func GenCurveCmd(_ context.Context, n, a, b, o int64) error {
 	// 	fmt.Println("Subgroup order is NOT prime")
 	// }
 
-
 	return nil
 }
 
+// maxOrderPoint returns a point of the largest order found among a few
+// random points, and its order. The order of a point is found by
+// removing prime factors from the number of points n, as long as the
+// result still maps the point to the identity.
+func maxOrderPoint(c *ec.Curve, n int64) (ec.Point, int64) {
+	var g ec.Point
+	var best int64
+
+	for i := 0; i < 20 && best < n; i++ {
+		var p = c.RandomPoint()
+		var order = n
+
+		for _, f := range smath.PrimeFactors(n) {
+			if c.ScalarM(order/f, p).Inf {
+				order /= f
+			}
+		}
+
+		if order > best {
+			g, best = p, order
+		}
+	}
+
+	return g, best
+}
+
 // GenCurveCmd generates the curve.
 // Using the curve's parameter is searches for a good generator point and
 // halts once one is found.
@@ -124,11 +175,6 @@ func GenCurveCmd2(_ context.Context, n, a, b int64) error {
 	SafePrintf("Searching for generator for curve %d %d %d\n",
 		n, a, b)
 
-	// Make sure curve is congruent 3 mod 4.
-	if r := n % 4; r != 3 {
-		return fmt.Errorf("%d is not congruent 3 mod 4, use another field", n)
-	}
-
 	if c, err = ec.NewCurve(field.NewFinite(n), a, b); err != nil {
 		return fmt.Errorf("failed to generate curve: %w", err)
 	}
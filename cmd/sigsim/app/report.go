@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kommendorkapten/sigsim/pkg/ec"
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+// demoCurves are the curves which can be selected by name.
+var demoCurves = map[string]func() (*ec.Curve, error){
+	"demo25": func() (*ec.Curve, error) {
+		return ec.DemoCurve25, nil
+	},
+	"supersingular": func() (*ec.Curve, error) {
+		return ec.DemoSupersingular, nil
+	},
+	"anomalous": func() (*ec.Curve, error) {
+		return ec.DemoAnomalous, nil
+	},
+	"montgomery": ec.DemoMontgomery.Weierstrass,
+	"smooth": func() (*ec.Curve, error) {
+		return ec.DemoSmooth, nil
+	},
+}
+
+// Report returns a command which prints the security report for a
+// curve.
+func Report() *ffcli.Command {
+	var (
+		flagset = flag.NewFlagSet("sigsim report", flag.ExitOnError)
+		name    = flagset.String("c", "",
+			"Named curve, one of "+strings.Join(curveNames(), ", "))
+		p  = flagset.Int64("p", 33489583, "Order of the finite field")
+		a  = flagset.Int64("a", -3, "A parameter for curve")
+		b  = flagset.Int64("b", 3411011, "B parameter for curve")
+		gx = flagset.Int64("gx", 12272011, "X coordinate of generator")
+		gy = flagset.Int64("gy", 8490180, "Y coordinate of generator")
+		n  = flagset.Int64("n", 33480829, "Order of generator")
+		j  = flagset.Bool("json", false, "Print the report as JSON")
+	)
+
+	return &ffcli.Command{
+		Name:       "report",
+		ShortUsage: "sigsim report [-c name | -p p -a a -b b ...]",
+		ShortHelp:  "Evaluate the security of a curve",
+		LongHelp: "Evaluate a curve against the SafeCurves criteria. " +
+			"The curve is either a named curve, or given by its " +
+			"parameters.",
+		FlagSet: flagset,
+		Exec: func(ctx context.Context, args []string) error {
+			var c *ec.Curve
+			var err error
+
+			if *name != "" {
+				var f, ok = demoCurves[*name]
+				if !ok {
+					return fmt.Errorf("unknown curve %s", *name)
+				}
+				if c, err = f(); err != nil {
+					return err
+				}
+			} else {
+				var f = field.NewFinite(*p)
+
+				if c, err = ec.NewCurve(f, f.Canonicalize(*a),
+					f.Canonicalize(*b)); err != nil {
+					return fmt.Errorf("failed to create curve: %w", err)
+				}
+				c.G = ec.Point{X: *gx, Y: *gy}
+				c.N = *n
+			}
+
+			return ReportCmd(ctx, c, *j)
+		},
+	}
+}
+
+// ReportCmd prints the security report for the curve, either as text
+// or as JSON.
+func ReportCmd(_ context.Context, c *ec.Curve, j bool) error {
+	var r, err = c.SecurityReport()
+	if err != nil {
+		return fmt.Errorf("failed to evaluate curve: %w", err)
+	}
+
+	if !j {
+		SafePrintf("%s", r)
+		SafePrintf("safe: %t\n", r.Safe())
+
+		return nil
+	}
+
+	var b []byte
+	if b, err = json.MarshalIndent(r, "", "  "); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	SafePrintf("%s\n", b)
+
+	return nil
+}
+
+// curveNames returns the sorted names of the demo curves.
+func curveNames() []string {
+	var names []string
+
+	for n := range demoCurves {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	return names
+}
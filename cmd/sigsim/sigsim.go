@@ -22,6 +22,9 @@ func main() {
 		Subcommands: []*ffcli.Command{
 			app.GenCurve(),
 			app.GenPrime(),
+			app.Report(),
+			app.BreakKey(),
+			app.RhoWorker(),
 			//			app.Sign(),
 		},
 		Exec: func(context.Context, []string) error {
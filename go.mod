@@ -2,10 +2,13 @@ module github.com/kommendorkapten/sigsim
 
 go 1.21.3
 
+require (
+	github.com/peterbourgon/ff/v3 v3.4.0
+	github.com/stretchr/testify v1.8.4
+)
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/peterbourgon/ff/v3 v3.4.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.8.4 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
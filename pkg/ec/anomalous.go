@@ -0,0 +1,334 @@
+package ec
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+)
+
+// cmJ holds the j-invariants for the imaginary quadratic fields
+// Q(sqrt(-D)) of class number one, where D = 3 mod 8. A curve with this
+// j-invariant has complex multiplication by the ring of integers of
+// Q(sqrt(-D)), and its number of points over F_p is p + 1 - t where
+// 4p = t^2 + Dv^2.
+var cmJ = map[int64]int64{
+	11:  -32768,
+	19:  -884736,
+	43:  -884736000,
+	67:  -147197952000,
+	163: -262537412640768000,
+}
+
+// NewAnomalousCurve returns an anomalous curve, i.e a curve with
+// exactly p points, over a field of the given bit length. The curve is
+// constructed with the complex multiplication method using the
+// discriminant -d, which must be one of 11, 19, 43, 67 and 163.
+// The smallest odd v giving a prime p = (1 + dv^2) / 4 of the right
+// size is used, so the result is deterministic. Anomalous curves are
+// insecure, see Smart.
+func NewAnomalousCurve(d int64, bits int) (*Curve, error) {
+	var j, ok = cmJ[d]
+	if !ok {
+		return nil, fmt.Errorf("unsupported discriminant %d", d)
+	}
+
+	if bits < 8 || bits > 62 {
+		return nil, fmt.Errorf("bit length %d is not in [8, 62]", bits)
+	}
+
+	// Smallest v with 4p >= 2^(bits+1)
+	var v = new(big.Int).Lsh(one, uint(bits+1))
+	v.Quo(v, big.NewInt(d))
+	v.Sqrt(v)
+	v.SetBit(v, 0, 1)
+
+	for ; ; v.Add(v, big.NewInt(2)) {
+		var p = new(big.Int).Mul(v, v)
+
+		p.Mul(p, big.NewInt(d))
+		p.Add(p, one)
+		p.Rsh(p, 2)
+
+		if p.BitLen() < bits {
+			continue
+		}
+		if p.BitLen() > bits {
+			return nil, fmt.Errorf("no %d bit prime for discriminant %d",
+				bits, d)
+		}
+		if !p.ProbablyPrime(32) {
+			continue
+		}
+
+		return anomalousCurve(field.NewFinite(p.Int64()), j)
+	}
+}
+
+// anomalousCurve returns the curve of trace one with j-invariant j,
+// y^2 = x^3 + 3kx + 2k with k = j / (1728 - j), or its quadratic twist.
+func anomalousCurve(f *field.Finite, j int64) (*Curve, error) {
+	// j is much larger than p, reduce it before canonicalizing
+	j = f.Canonicalize(j % f.P())
+
+	var k, err = f.Inverse(f.Add(1728, f.Neg(j)))
+	if err != nil {
+		return nil, err
+	}
+
+	k = f.Multiply(k, j)
+
+	var c *Curve
+	if c, err = NewCurve(f, f.Multiply(3, k), f.Multiply(2, k)); err != nil {
+		return nil, err
+	}
+
+	c.N = f.P()
+	c.G = c.generator()
+	if !c.ScalarM(c.N, c.G).Inf {
+		// The curve has p + 2 points, use the twist, a -> ac^2 and
+		// b -> bc^3 where c is a non-square
+		var n = int64(2)
+		for f.Legendre(n) != -1 {
+			n++
+		}
+
+		var n2 = f.Multiply(n, n)
+		if c, err = NewCurve(f, f.Multiply(c.A, n2),
+			f.Multiply(c.B, f.Multiply(n2, n))); err != nil {
+			return nil, err
+		}
+
+		c.N = f.P()
+		c.G = c.generator()
+	}
+
+	if !c.ScalarM(c.N, c.G).Inf {
+		return nil, errors.New("curve is not anomalous")
+	}
+
+	return c, nil
+}
+
+// generator returns the point with the smallest x coordinate. For a
+// curve of prime order every point but the identity is a generator.
+func (c *Curve) generator() Point {
+	for x := int64(0); ; x++ {
+		if y, err := c.Y(x); err == nil {
+			return Point{X: x, Y: y}
+		}
+	}
+}
+
+// Smart solves the discrete logarithm q = kp on an anomalous curve, a
+// curve with exactly p points, using Smart's attack. The attack runs in
+// polynomial time.
+// The curve and the points are lifted to Z/p^2. Multiplying a lifted
+// point by p gives a point which reduces to the identity mod p, and
+// whose parameter -x/y is divisible by p. This is the p-adic elliptic
+// logarithm, which is linear, so k = psi(q) / psi(p) mod p.
+// See N. Smart, The discrete logarithm problem on elliptic curves of
+// trace one.
+func (c *Curve) Smart(p, q Point) (int64, error) {
+	var pm = big.NewInt(c.F.P())
+
+	if p.Inf {
+		return 0, errors.New("base point is the identity element")
+	}
+
+	// By Hasse's theorem, a point of order p means the curve has exactly
+	// p points
+	if !c.ScalarM(c.F.P(), p).Inf {
+		return 0, errors.New("curve is not anomalous")
+	}
+
+	if q.Inf {
+		return 0, nil
+	}
+
+	// Try a few lifts of a, the attack fails for the canonical lift
+	for r := int64(1); r < 8; r++ {
+		var l = newLift(c, r)
+		// Fix b with p, then lift q onto the same curve
+		var pp = l.point(p, nil)
+		l.b = l.bFor(pp)
+		var qp = l.point(q, l.b)
+
+		var psiP, errP = l.psi(pp)
+		var psiQ, errQ = l.psi(qp)
+
+		if errP != nil || errQ != nil {
+			continue
+		}
+
+		var k = new(big.Int).ModInverse(psiP, pm)
+		if k == nil {
+			continue
+		}
+
+		k.Mul(k, psiQ)
+		k.Mod(k, pm)
+
+		return k.Int64(), nil
+	}
+
+	return 0, errors.New("no usable lift found")
+}
+
+// lift is a curve y^2 = x^3 + ax + b over Z/p^2, with a = a0 + rp.
+type lift struct {
+	p  *big.Int
+	p2 *big.Int
+	a  *big.Int
+	b  *big.Int
+}
+
+// liftPoint is an affine point over Z/p^2.
+type liftPoint struct {
+	x, y *big.Int
+}
+
+func newLift(c *Curve, r int64) *lift {
+	var p = big.NewInt(c.F.P())
+	var l = lift{
+		p:  p,
+		p2: new(big.Int).Mul(p, p),
+		a:  new(big.Int).Mul(big.NewInt(r), p),
+	}
+
+	l.a.Add(l.a, big.NewInt(c.A))
+
+	return &l
+}
+
+// bFor returns b such that p is on the lifted curve.
+func (l *lift) bFor(p liftPoint) *big.Int {
+	var b = new(big.Int).Mul(p.y, p.y)
+
+	b.Sub(b, l.rhs(p.x, new(big.Int)))
+
+	return b.Mod(b, l.p2)
+}
+
+// rhs returns x^3 + ax + b mod p^2.
+func (l *lift) rhs(x, b *big.Int) *big.Int {
+	var r = new(big.Int).Mul(x, x)
+
+	r.Add(r, l.a)
+	r.Mul(r, x)
+	r.Add(r, b)
+
+	return r.Mod(r, l.p2)
+}
+
+// point lifts p. If b is nil p is used as is, otherwise y is lifted with
+// Hensel's lemma so that the point is on the curve with parameter b:
+// (y + tp)^2 = rhs mod p^2 gives t = ((rhs - y^2) / p) / 2y mod p.
+func (l *lift) point(p Point, b *big.Int) liftPoint {
+	var lp = liftPoint{x: big.NewInt(p.X), y: big.NewInt(p.Y)}
+
+	if b == nil {
+		return lp
+	}
+
+	var t = l.rhs(lp.x, b)
+
+	t.Sub(t, new(big.Int).Mul(lp.y, lp.y))
+	t.Mod(t, l.p2)
+	t.Quo(t, l.p)
+
+	var inv = new(big.Int).Lsh(lp.y, 1)
+	inv.ModInverse(inv, l.p)
+	t.Mul(t, inv)
+	t.Mod(t, l.p)
+
+	lp.y.Add(lp.y, t.Mul(t, l.p))
+
+	return lp
+}
+
+// add returns p + q mod p^2, or an error if the slope is not defined.
+func (l *lift) add(p, q liftPoint) (liftPoint, error) {
+	var num, den = new(big.Int), new(big.Int)
+
+	if p.x.Cmp(q.x) == 0 && p.y.Cmp(q.y) == 0 {
+		// 3x^2 + a / 2y
+		num.Mul(p.x, p.x)
+		num.Mul(num, big.NewInt(3))
+		num.Add(num, l.a)
+		den.Lsh(p.y, 1)
+	} else {
+		num.Sub(q.y, p.y)
+		den.Sub(q.x, p.x)
+	}
+
+	den.Mod(den, l.p2)
+	if den.ModInverse(den, l.p2) == nil {
+		return p, errors.New("slope is not defined")
+	}
+
+	var m = num.Mul(num, den)
+	m.Mod(m, l.p2)
+
+	var r = liftPoint{x: new(big.Int), y: new(big.Int)}
+
+	r.x.Mul(m, m)
+	r.x.Sub(r.x, p.x)
+	r.x.Sub(r.x, q.x)
+	r.x.Mod(r.x, l.p2)
+	r.y.Sub(p.x, r.x)
+	r.y.Mul(r.y, m)
+	r.y.Sub(r.y, p.y)
+	r.y.Mod(r.y, l.p2)
+
+	return r, nil
+}
+
+// psi returns -x/y of pP, divided by p, mod p.
+// r = (p - 1)P does not reduce to the identity, so it's computed with
+// affine arithmetic mod p^2. r = -P mod p, so in the last addition
+// r + P, x2 - x1 = up and the slope is m = mu/p with mu = (y2 - y1)/u.
+// The sum has -x/y = p/mu mod p^2, so psi is 1/mu mod p.
+func (l *lift) psi(p liftPoint) (*big.Int, error) {
+	var k = new(big.Int).Sub(l.p, one)
+	var r liftPoint
+	var started bool
+	var err error
+
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		if started {
+			if r, err = l.add(r, r); err != nil {
+				return nil, err
+			}
+		}
+
+		if k.Bit(i) == 1 {
+			if !started {
+				r, started = p, true
+			} else if r, err = l.add(r, p); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// u = (x2 - x1) / p, must be a unit mod p
+	var u = new(big.Int).Sub(p.x, r.x)
+	u.Mod(u, l.p2)
+	u.Quo(u, l.p)
+	if new(big.Int).ModInverse(u, l.p) == nil {
+		// The canonical lift, pP is the identity mod p^2
+		return nil, errors.New("canonical lift")
+	}
+
+	// 1 / mu = u / (y2 - y1)
+	var d = new(big.Int).Sub(p.y, r.y)
+	d.Mod(d, l.p)
+	if d.ModInverse(d, l.p) == nil {
+		return nil, errors.New("point of order two")
+	}
+
+	u.Mul(u, d)
+
+	return u.Mod(u, l.p), nil
+}
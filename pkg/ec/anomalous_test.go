@@ -0,0 +1,73 @@
+package ec
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAnomalousCurve(t *testing.T) {
+	for _, d := range []int64{11, 19, 43, 67, 163} {
+		var c, err = NewAnomalousCurve(d, 40)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 40, c.BS)
+		// By Hasse's theorem, a point of order p means p points
+		assert.True(t, c.ScalarM(c.N, c.RandomPoint()).Inf, "d %d", d)
+	}
+
+	var c, err = NewAnomalousCurve(11, 31)
+	assert.Nil(t, err)
+	assert.Equal(t, DemoAnomalous.String(), c.String())
+	var n int64
+	n, err = c.CountPoints()
+	assert.Nil(t, err)
+	assert.Equal(t, c.N, n)
+
+	_, err = NewAnomalousCurve(7, 31)
+	assert.NotNil(t, err)
+
+	// Small fields, where j is much larger than p
+	for _, d := range []int64{11, 19, 43, 67, 163} {
+		for _, bits := range []int{8, 12, 16} {
+			var c, err = NewAnomalousCurve(d, bits)
+			if err != nil {
+				// No prime of that size for d
+				assert.ErrorContains(t, err, "no ", "d %d", d)
+				continue
+			}
+
+			assert.Equal(t, bits, c.BS, "d %d", d)
+			assert.Equal(t, c.F.P(), c.CountPointsLegendre(),
+				"d %d bits %d", d, bits)
+		}
+	}
+}
+
+func TestSmart(t *testing.T) {
+	var c = DemoAnomalous
+
+	for i := 0; i < 10; i++ {
+		var k, _ = rand.Int(rand.Reader, big.NewInt(c.N))
+		var p = c.RandomPoint()
+		var q = c.ScalarM(k.Int64(), p)
+		var d, err = c.Smart(p, q)
+
+		assert.Nil(t, err)
+		assert.Equal(t, k.Int64(), d)
+	}
+
+	var d, err = c.Smart(c.G, Point{Inf: true})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), d)
+
+	_, err = DemoCurve25.Smart(DemoCurve25.G, DemoCurve25.G)
+	assert.NotNil(t, err)
+
+	// Verify rejects the curve
+	var failed = c.VerifyReport().Failed()
+	assert.Len(t, failed, 1)
+	assert.Equal(t, "anomalous", failed[0].Name)
+}
@@ -0,0 +1,330 @@
+package ec
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	smath "github.com/kommendorkapten/sigsim/pkg/math"
+)
+
+// BinaryPoint represents a point on a BinaryCurve.
+// If the point is the identity element, Inf is set to true.
+type BinaryPoint struct {
+	X   uint64
+	Y   uint64
+	Inf bool
+}
+
+// BinaryCurve represents an elliptic curve over a binary field
+// GF(2^m), y^2 + xy = x^3 + ax^2 + b.
+// The short Weierstrass form can't be used in characteristic two, as
+// the curve would always be singular. This is the form for the
+// non-supersingular curves, which are the ones used in cryptography.
+// If a and b are in {0, 1} the curve is a Koblitz curve, those are
+// defined over GF(2) which allows the points to be counted quickly.
+type BinaryCurve struct {
+	F *field.Binary
+	A uint64      // A parameter
+	B uint64      // B parameter
+	G BinaryPoint // Generator point
+	N int64       // Order of the generator point
+	H int64       // Cofactor, the number of points divided by N
+}
+
+// DemoKoblitz is a toy Koblitz curve over GF(2^41), with a = 0 and
+// b = 1. The number of points is 4 times a prime.
+var DemoKoblitz = &BinaryCurve{
+	F: mustBinary(41, 1<<41|1<<3|1),
+	A: 0,
+	B: 1,
+	G: BinaryPoint{
+		X: 0x17cfbd49442,
+		Y: 0xf740e984f8,
+	},
+	N: 549756390943,
+	H: 4,
+}
+
+// mustBinary returns GF(2^m) and panics on any error.
+func mustBinary(m int, poly uint64) *field.Binary {
+	var f, err = field.NewBinary(m, poly)
+	if err != nil {
+		panic(err)
+	}
+
+	return f
+}
+
+// NewBinaryCurve returns the curve y^2 + xy = x^3 + ax^2 + b over the
+// binary field. If b is zero the curve is singular, and an error is
+// returned.
+func NewBinaryCurve(f *field.Binary, a, b uint64) (*BinaryCurve, error) {
+	if b == 0 || !f.Element(a) || !f.Element(b) {
+		return nil, fmt.Errorf(
+			"provided parameters are not valid, a:%#x b:%#x",
+			a, b,
+		)
+	}
+
+	return &BinaryCurve{F: f, A: a, B: b}, nil
+}
+
+func (c *BinaryCurve) String() string {
+	return fmt.Sprintf("%s %#x %#x %+v %d %d",
+		c.F,
+		c.A,
+		c.B,
+		c.G,
+		c.N,
+		c.H,
+	)
+}
+
+// Koblitz returns true if the curve is defined over GF(2), i.e a and b
+// are zero or one.
+func (c *BinaryCurve) Koblitz() bool {
+	return c.A <= 1 && c.B == 1
+}
+
+// rhs computes x^3 + ax^2 + b.
+func (c *BinaryCurve) rhs(x uint64) uint64 {
+	var f = c.F
+	var x2 = f.Square(x)
+
+	return f.Add(f.Multiply(f.Add(x, c.A), x2), c.B)
+}
+
+// Valid returns true if the provided point is a valid curve point.
+func (c *BinaryCurve) Valid(p BinaryPoint) bool {
+	if p.Inf {
+		return true
+	}
+
+	if !c.F.Element(p.X) || !c.F.Element(p.Y) {
+		return false
+	}
+
+	var f = c.F
+	var lhs = f.Add(f.Square(p.Y), f.Multiply(p.X, p.Y))
+
+	return lhs == c.rhs(p.X)
+}
+
+// Y returns a y coordinate on the curve for a given x coordinate, the
+// other one is x + y. If x is not on the curve, an error is returned.
+// For x = 0, y = sqrt(b). Otherwise let y = xz, then z^2 + z =
+// x + a + b/x^2, which is solvable iff the trace of the right hand side
+// is zero.
+func (c *BinaryCurve) Y(x uint64) (uint64, error) {
+	var f = c.F
+
+	if x == 0 {
+		return f.Sqrt(c.B), nil
+	}
+
+	var inv, err = f.Inverse(f.Square(x))
+	if err != nil {
+		return 0, err
+	}
+
+	var z uint64
+	if z, err = f.SolveQuadratic(f.Multiply(c.rhs(x), inv)); err != nil {
+		return 0, fmt.Errorf("%#x is not on the curve", x)
+	}
+
+	return f.Multiply(x, z), nil
+}
+
+// RandomPoint returns a random point on the curve.
+func (c *BinaryCurve) RandomPoint() BinaryPoint {
+	var max = new(big.Int).Lsh(big.NewInt(1), uint(c.F.M()))
+
+	for {
+		var x, err = rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+
+		var p = BinaryPoint{X: x.Uint64()}
+
+		if p.Y, err = c.Y(p.X); err != nil {
+			continue
+		}
+
+		return p
+	}
+}
+
+// Neg returns the inverse of p, (x, x + y).
+func (c *BinaryCurve) Neg(p BinaryPoint) BinaryPoint {
+	if p.Inf {
+		return p
+	}
+
+	return BinaryPoint{X: p.X, Y: c.F.Add(p.X, p.Y)}
+}
+
+// Add two points together and returns the resulting point.
+// If p and q are the same point, p is doubled.
+// With l = (y1 + y2) / (x1 + x2), or l = x1 + y1/x1 when doubling:
+// x3 = l^2 + l + x1 + x2 + a
+// y3 = l(x1 + x3) + x3 + y1
+func (c *BinaryCurve) Add(p, q BinaryPoint) BinaryPoint {
+	var f = c.F
+	var l, inv uint64
+	var err error
+
+	if p.Inf {
+		return q
+	}
+
+	if q.Inf {
+		return p
+	}
+
+	if p.X == q.X {
+		if p.Y != q.Y || p.X == 0 {
+			// p = -q, this includes the point of order two
+			return BinaryPoint{Inf: true}
+		}
+
+		inv, err = f.Inverse(p.X)
+		l = f.Add(p.X, f.Multiply(p.Y, inv))
+	} else {
+		inv, err = f.Inverse(f.Add(p.X, q.X))
+		l = f.Multiply(f.Add(p.Y, q.Y), inv)
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	var r BinaryPoint
+
+	r.X = f.Add(f.Add(f.Square(l), l), f.Add(f.Add(p.X, q.X), c.A))
+	r.Y = f.Add(f.Add(f.Multiply(l, f.Add(p.X, r.X)), r.X), p.Y)
+
+	return r
+}
+
+// ScalarM calculates the scalar multiplication of a point, using
+// double-and-add.
+func (c *BinaryCurve) ScalarM(k int64, p BinaryPoint) BinaryPoint {
+	var r = BinaryPoint{Inf: true}
+
+	if k < 0 {
+		k = -k
+		p = c.Neg(p)
+	}
+
+	for ; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			r = c.Add(r, p)
+		}
+
+		p = c.Add(p, p)
+	}
+
+	return r
+}
+
+// CountPoints returns the number of points on the curve.
+// For a Koblitz curve, let t = 3 - #E(GF(2)) be the trace of the
+// Frobenius over GF(2). Then #E(GF(2^m)) = 2^m + 1 - V_m, where
+// V_0 = 2, V_1 = t and V_k = tV_(k-1) - 2V_(k-2).
+// Other curves are counted one x at a time, which is linear in 2^m,
+// use with caution.
+func (c *BinaryCurve) CountPoints() int64 {
+	var f = c.F
+	var n = new(big.Int).Lsh(big.NewInt(1), uint(f.M()))
+
+	n.Add(n, big.NewInt(1))
+	if !c.Koblitz() {
+		var x uint64
+
+		// x = 0 has a single point, (0, sqrt(b))
+		n.SetInt64(2)
+		for x = 1; f.Element(x); x++ {
+			var inv, _ = f.Inverse(f.Square(x))
+
+			if f.Trace(f.Multiply(c.rhs(x), inv)) == 0 {
+				n.Add(n, big.NewInt(2))
+			}
+		}
+
+		return n.Int64()
+	}
+
+	// Over GF(2), a = 0 gives 4 points and a = 1 gives 2
+	var t = big.NewInt(-1)
+	if c.A == 1 {
+		t.SetInt64(1)
+	}
+
+	var v0, v1 = big.NewInt(2), new(big.Int).Set(t)
+	for k := 1; k < f.M(); k++ {
+		var v = new(big.Int).Mul(t, v1)
+
+		v.Sub(v, v0.Lsh(v0, 1))
+		v0, v1 = v1, v
+	}
+
+	n.Sub(n, v1)
+	if !n.IsInt64() {
+		panic(fmt.Sprintf("number of points %s does not fit in 64 bits",
+			n))
+	}
+
+	return n.Int64()
+}
+
+// Order returns the order of p. The order is found by removing prime
+// factors from the number of points, as long as the result still maps p
+// to the identity.
+func (c *BinaryCurve) Order(p BinaryPoint) int64 {
+	var n = c.CountPoints()
+
+	if c.N != 0 && c.H != 0 {
+		n = c.N * c.H
+	}
+
+	for _, q := range smath.PrimeFactors(n) {
+		if c.ScalarM(n/q, p).Inf {
+			n /= q
+		}
+	}
+
+	return n
+}
+
+// Verify verifies all the parameters of the curve.
+func (c *BinaryCurve) Verify() error {
+	if c.B == 0 {
+		return errors.New("curve is singular")
+	}
+
+	if !c.Valid(c.G) {
+		return errors.New("generator point is not on curve")
+	}
+
+	if c.G.Inf {
+		return errors.New("generator point is the identity element")
+	}
+
+	if !big.NewInt(c.N).ProbablyPrime(256) {
+		return fmt.Errorf("curve order %d is not prime", c.N)
+	}
+
+	if o := c.Order(c.G); o != c.N {
+		return fmt.Errorf("order %d of generator does not match %d", o, c.N)
+	}
+
+	if n := c.CountPoints(); n != c.N*c.H {
+		return fmt.Errorf("number of points %d is not %d * %d", n, c.N, c.H)
+	}
+
+	return nil
+}
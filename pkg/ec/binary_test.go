@@ -0,0 +1,69 @@
+package ec
+
+import (
+	"testing"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDemoKoblitz(t *testing.T) {
+	var c = DemoKoblitz
+
+	assert.True(t, c.Koblitz())
+	assert.Nil(t, c.Verify())
+	assert.True(t, c.ScalarM(c.N, c.G).Inf)
+	assert.Equal(t, c.Neg(c.G), c.ScalarM(c.N-1, c.G))
+
+	for i := 0; i < 20; i++ {
+		var p = c.RandomPoint()
+		var q = c.RandomPoint()
+
+		assert.True(t, c.Valid(p))
+		assert.True(t, c.Valid(c.Add(p, q)))
+		assert.Equal(t, c.Add(p, q), c.Add(q, p))
+		assert.True(t, c.Add(p, c.Neg(p)).Inf)
+		assert.Equal(t, c.ScalarM(3, p), c.Add(p, c.Add(p, p)))
+		assert.True(t, c.ScalarM(c.N*c.H, p).Inf)
+	}
+}
+
+func TestBinaryCountPoints(t *testing.T) {
+	var f, _ = field.NewBinary(7, 0x83)
+	var tests = []struct {
+		name string
+		a, b uint64
+	}{
+		{name: "koblitz a=0", a: 0, b: 1},
+		{name: "koblitz a=1", a: 1, b: 1},
+		{name: "random", a: 0x2b, b: 0x51},
+		{name: "a=0", a: 0, b: 0x7f},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c, err = NewBinaryCurve(f, tt.a, tt.b)
+			assert.Nil(t, err)
+
+			// Count all points by brute force
+			var n = int64(1)
+			var x, y uint64
+			for x = 0; f.Element(x); x++ {
+				for y = 0; f.Element(y); y++ {
+					if c.Valid(BinaryPoint{X: x, Y: y}) {
+						n++
+					}
+				}
+			}
+
+			assert.Equal(t, n, c.CountPoints())
+
+			var p = c.RandomPoint()
+			assert.True(t, c.ScalarM(n, p).Inf)
+			assert.Equal(t, int64(0), n%c.Order(p))
+		})
+	}
+
+	var _, err = NewBinaryCurve(f, 1, 0)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,89 @@
+package ec
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// MaxBabySteps is the largest number of baby steps stored by SolveDLP,
+// which bounds its memory use. Each baby step takes about 40 bytes. If
+// sqrt(n) baby steps don't fit, more giant steps are taken instead.
+var MaxBabySteps int64 = 1 << 22
+
+// SolveDLP solves the discrete logarithm q = kg, where g has order n,
+// with the baby-step giant-step algorithm. With m baby steps jg,
+// 0 <= j <= m, stored in a hash table indexed by the x coordinate, the
+// giant steps q - 2mig are looked up until a match is found, then
+// k = 2mi +/- j. Each x coordinate covers both jg and -jg, which is why
+// the giant step is 2m.
+// m is sqrt(n) / 2 if that fits in MaxBabySteps, making the number of
+// giant steps the same. Otherwise m is MaxBabySteps, and the giant
+// steps are in total n / 2m.
+// If q is not a multiple of g, ErrNoLog is returned.
+func (c *Curve) SolveDLP(ctx context.Context, g, q Point, n int64) (int64,
+	error) {
+	if g.Inf {
+		return 0, errors.New("base point is the identity element")
+	}
+	if n <= 0 {
+		return 0, errors.New("order must be positive")
+	}
+
+	var m = int64(math.Ceil(math.Sqrt(float64(n)) / 2))
+	m = max(1, min(m, MaxBabySteps))
+
+	// Baby steps, x -> j for jG, j in [1, m]
+	var baby = make(map[int64]int64, m)
+	var p = g
+	for j := int64(1); j <= m; j++ {
+		if j%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+
+		if p.Inf {
+			// g has order j <= m
+			break
+		}
+		if _, ok := baby[p.X]; !ok {
+			baby[p.X] = j
+		}
+		p = c.Add(p, g)
+	}
+
+	// Giant steps, r = q - 2mig
+	var stride = 2 * m
+	var giant = c.ScalarM(n-stride%n, g)
+	var r = q
+
+	for i := int64(0); i*stride < n+stride; i++ {
+		if i%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+
+		if r.Inf {
+			return (i * stride) % n, nil
+		}
+
+		if j, ok := baby[r.X]; ok {
+			// r = jg or r = -jg
+			for _, k := range []int64{i*stride + j, i*stride - j} {
+				k %= n
+				if k < 0 {
+					k += n
+				}
+				if c.ScalarM(k, g).Equal(q) {
+					return k, nil
+				}
+			}
+		}
+
+		r = c.Add(r, giant)
+	}
+
+	return 0, ErrNoLog
+}
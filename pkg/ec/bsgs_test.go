@@ -0,0 +1,76 @@
+package ec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolveDLP(t *testing.T) {
+	var c = DemoCurve25
+	var tests = []struct {
+		name string
+		max  int64
+		k    int64
+	}{
+		{name: "default", max: MaxBabySteps, k: 847079},
+		{name: "default", max: MaxBabySteps, k: 0},
+		{name: "default", max: MaxBabySteps, k: 1},
+		{name: "default", max: MaxBabySteps, k: c.N - 1},
+		{name: "segmented", max: 1000, k: 847079},
+		{name: "segmented", max: 1000, k: c.N - 1},
+		{name: "segmented", max: 1, k: 31337},
+	}
+
+	var saved = MaxBabySteps
+	defer func() {
+		MaxBabySteps = saved
+	}()
+
+	for _, tc := range tests {
+		MaxBabySteps = tc.max
+
+		var k, err = c.SolveDLP(context.Background(), c.G,
+			c.ScalarM(tc.k, c.G), c.N)
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tc.k, k, tc.name)
+	}
+}
+
+func TestSolveDLPSmallOrder(t *testing.T) {
+	// Points of small order on a curve with cofactor four
+	var c = DemoSupersingular
+	var g = c.ScalarM(c.N, c.RandomPoint())
+
+	for g.Inf {
+		g = c.ScalarM(c.N, c.RandomPoint())
+	}
+
+	var n = c.Order(g)
+	for k := int64(0); k < n; k++ {
+		var got, err = c.SolveDLP(context.Background(), g,
+			c.ScalarM(k, g), n)
+		assert.Nil(t, err)
+		assert.Equal(t, k, got)
+	}
+}
+
+func TestSolveDLPNoLog(t *testing.T) {
+	var c = DemoSupersingular
+	var q = c.RandomPoint()
+
+	// q is not in the subgroup generated by G
+	for c.ScalarM(c.N, q).Inf {
+		q = c.RandomPoint()
+	}
+
+	var _, err = c.SolveDLP(context.Background(), c.G, q, c.N)
+	assert.ErrorIs(t, err, ErrNoLog)
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	_, err = c.SolveDLP(ctx, c.G, q, c.N)
+	assert.ErrorIs(t, err, context.Canceled)
+}
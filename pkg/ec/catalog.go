@@ -0,0 +1,132 @@
+package ec
+
+import (
+	"math/big"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+)
+
+// This file contains standardized curves. They are large enough that
+// they must be used as a BigCurve.
+
+// P256 is the NIST P-256 curve (also known as secp256r1).
+// nolint: lll
+// nolint: revive
+// See https://nvlpubs.nist.gov/nistpubs/SpecialPublications/NIST.SP.800-186.pdf
+var P256 = mustCurve(
+	"ffffffff00000001000000000000000000000000ffffffffffffffffffffffff",
+	"ffffffff00000001000000000000000000000000fffffffffffffffffffffffc",
+	"5ac635d8aa3a93e7b3ebbd55769886bc651d06b0cc53b0f63bce3c3e27d2604b",
+	"6b17d1f2e12c4247f8bce6e563a440f277037d812deb33a0f4a13945d898c296",
+	"4fe342e2fe1a7f9b8ee7eb4a7c0f9e162bce33576b315ececbb6406837bf51f5",
+	"ffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551",
+)
+
+// P384 is the NIST P-384 curve (also known as secp384r1).
+var P384 = mustCurve(
+	"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe"+
+		"ffffffff0000000000000000ffffffff",
+	"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe"+
+		"ffffffff0000000000000000fffffffc",
+	"b3312fa7e23ee7e4988e056be3f82d19181d9c6efe8141120314088f5013875a"+
+		"c656398d8a2ed19d2a85c8edd3ec2aef",
+	"aa87ca22be8b05378eb1c71ef320ad746e1d3b628ba79b9859f741e082542a38"+
+		"5502f25dbf55296c3a545e3872760ab7",
+	"3617de4a96262c6f5d9e98bf9292dc29f8f41dbd289a147ce9da3113b5f0b8c0"+
+		"0a60b1ce1d7e819d7a431d7c90ea0e5f",
+	"ffffffffffffffffffffffffffffffffffffffffffffffffc7634d81f4372ddf"+
+		"581a0db248b0a77aecec196accc52973",
+)
+
+// Secp256k1 is the curve used by Bitcoin, Y^2 = X^3 + 7.
+// See https://www.secg.org/sec2-v2.pdf
+var Secp256k1 = mustCurve(
+	"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f",
+	"0",
+	"7",
+	"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+	"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+	"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+)
+
+// DemoSupersingular is the supersingular curve y^2 = x^3 + x over a
+// field with p = 3 mod 4. The number of points is p + 1 = 4N, so the
+// embedding degree is two, and the distortion map (x, y) -> (-x, iy),
+// where i^2 = -1 in F_p^2, maps points to a subgroup independent of G.
+// The curve is intended for pairings, but it's insecure by design as
+// the MOV attack maps the discrete logarithm into F_p^2.
+var DemoSupersingular = &Curve{
+	F: field.NewFinite(2147482867),
+	A: 1,
+	B: 0,
+	G: Point{
+		X: 1846166884,
+		Y: 1411018613,
+	},
+	N:  536870717,
+	BS: 29,
+}
+
+// DemoAnomalous is an anomalous curve, the number of points equals p.
+// It's generated with NewAnomalousCurve(11, 31), and the discrete
+// logarithm is easily solved with Smart's attack.
+var DemoAnomalous = &Curve{
+	F: field.NewFinite(1079090323),
+	A: 716724182,
+	B: 118119347,
+	G: Point{
+		X: 0,
+		Y: 154065205,
+	},
+	N:  1079090323,
+	BS: 31,
+}
+
+// DemoSmooth is a curve whose number of points, 2^3 * 3 * 5 * 179 * 257
+// * 389, has no prime factor larger than 1000. The curve equation is
+// found with sigsim genc -p 2147483647 -b 1 -smooth 1000, but G is a
+// random point of maximal order so it differs between runs. The group
+// is not cyclic, G has order half the number of points. The discrete
+// logarithm is easily solved with the Pohlig-Hellman algorithm.
+var DemoSmooth = &Curve{
+	F: field.NewFinite(2147483647),
+	A: 2147483644,
+	B: 101,
+	G: Point{
+		X: 1781875509,
+		Y: 2104659292,
+	},
+	N:  1073710020,
+	BS: 30,
+}
+
+// mustCurve creates a curve from hex encoded parameters and panics on
+// any error.
+func mustCurve(p, a, b, gx, gy, n string) *BigCurve {
+	var bp = mustHex(p)
+	var c, err = NewGenericCurve[*big.Int](field.NewBigFinite(bp),
+		mustHex(a),
+		mustHex(b),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	c.G = BigPoint{
+		X: mustHex(gx),
+		Y: mustHex(gy),
+	}
+	c.N = mustHex(n)
+	c.BS = c.N.BitLen()
+
+	return c
+}
+
+func mustHex(s string) *big.Int {
+	var z, ok = new(big.Int).SetString(s, 16)
+	if !ok {
+		panic(s)
+	}
+
+	return z
+}
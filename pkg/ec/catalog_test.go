@@ -0,0 +1,107 @@
+package ec
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog(t *testing.T) {
+	var tests = map[string]*BigCurve{
+		"P-256":     P256,
+		"P-384":     P384,
+		"secp256k1": Secp256k1,
+	}
+
+	for name, c := range tests {
+		assert.Nil(t, c.Verify(), name)
+	}
+}
+
+func TestCatalogBitSize(t *testing.T) {
+	var tests = map[string]*Curve{
+		"DemoCurve25":       DemoCurve25,
+		"DemoSupersingular": DemoSupersingular,
+		"DemoAnomalous":     DemoAnomalous,
+		"DemoSmooth":        DemoSmooth,
+	}
+
+	for name, c := range tests {
+		assert.Equal(t, big.NewInt(c.N).BitLen(), c.BS, name)
+	}
+}
+
+func TestCatalogMatchesStdlib(t *testing.T) {
+	var tests = []struct {
+		c   *BigCurve
+		ref elliptic.Curve
+	}{
+		{c: P256, ref: elliptic.P256()},
+		{c: P384, ref: elliptic.P384()},
+	}
+
+	for _, tc := range tests {
+		var ref = tc.ref.Params()
+		var cc = NewCompatCurve(tc.c)
+		var cp = cc.Params()
+		var a = new(big.Int).Sub(ref.P, big.NewInt(3))
+
+		assert.Equal(t, 0, ref.P.Cmp(cp.P), ref.Name)
+		assert.Equal(t, 0, ref.N.Cmp(cp.N), ref.Name)
+		assert.Equal(t, 0, ref.B.Cmp(cp.B), ref.Name)
+		assert.Equal(t, 0, ref.Gx.Cmp(cp.Gx), ref.Name)
+		assert.Equal(t, 0, ref.Gy.Cmp(cp.Gy), ref.Name)
+		assert.Equal(t, ref.BitSize, cp.BitSize, ref.Name)
+		assert.Equal(t, 0, a.Cmp(tc.c.A), ref.Name)
+
+		// Compare arithmetic
+		var k = []byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0}
+		var x1, y1 = cc.ScalarBaseMult(k)
+		var x2, y2 = tc.ref.ScalarBaseMult(k)
+
+		assert.Equal(t, 0, x1.Cmp(x2), ref.Name)
+		assert.Equal(t, 0, y1.Cmp(y2), ref.Name)
+		assert.True(t, cc.IsOnCurve(x1, y1))
+
+		x1, y1 = cc.Double(x1, y1)
+		x2, y2 = tc.ref.Double(x2, y2)
+		assert.Equal(t, 0, x1.Cmp(x2), ref.Name)
+		assert.Equal(t, 0, y1.Cmp(y2), ref.Name)
+
+		x1, y1 = cc.Add(x1, y1, ref.Gx, ref.Gy)
+		x2, y2 = tc.ref.Add(x2, y2, ref.Gx, ref.Gy)
+		assert.Equal(t, 0, x1.Cmp(x2), ref.Name)
+		assert.Equal(t, 0, y1.Cmp(y2), ref.Name)
+
+		// N*G is the identity element, (0, 0)
+		x1, y1 = cc.ScalarBaseMult(ref.N.Bytes())
+		assert.Zero(t, x1.Sign())
+		assert.Zero(t, y1.Sign())
+	}
+}
+
+func TestCompatSmallCurve(t *testing.T) {
+	var cc = NewCompatCurve(DemoCurve25)
+	var g = DemoCurve25.ScalarM(847079, DemoCurve25.G)
+	var x, y = cc.ScalarBaseMult(big.NewInt(847079).Bytes())
+
+	assert.Equal(t, g.X, x.Int64())
+	assert.Equal(t, g.Y, y.Int64())
+	assert.True(t, cc.IsOnCurve(x, y))
+	assert.False(t, cc.IsOnCurve(x, big.NewInt(g.Y+1)))
+}
+
+func TestDemoSupersingular(t *testing.T) {
+	var c = DemoSupersingular
+
+	assert.True(t, c.Valid(c.G))
+	assert.True(t, c.ScalarM(c.N, c.G).Inf)
+	var n, err = c.CountPoints()
+	assert.Nil(t, err)
+	assert.Equal(t, c.F.P()+1, n)
+	assert.Equal(t, 2, c.EmbeddingDegree(20))
+	assert.Equal(t, 0, DemoCurve25.EmbeddingDegree(20))
+	assert.Equal(t, 0, P256.EmbeddingDegree(100))
+}
@@ -7,55 +7,92 @@ import (
 
 // CompatCurve implements the Curve interface in crypto/elliptic
 // The usage of this is deprecated but, this is only to test compatibility.
+// All arithmetic is performed by the wrapped curve, so the results can
+// be compared with the implementations in crypto/elliptic.
 type CompatCurve struct {
 	cp *elliptic.CurveParams
+	c  *BigCurve
 }
 
 var _ elliptic.Curve = &CompatCurve{}
 
+// BigConverter is implemented by curves that can be represented over a
+// big.Int backed field, i.e Curve and GenericCurve.
+type BigConverter interface {
+	Big() *BigCurve
+}
+
 // NewCompatCurve wraps curve as elliptic.Curve.
-func NewCompatCurve(c *Curve) *CompatCurve {
+func NewCompatCurve(c BigConverter) *CompatCurve {
+	var bc = c.Big()
 	var cc = CompatCurve{
 		cp: &elliptic.CurveParams{
-			P:       big.NewInt(c.F.P()),
-			N:       big.NewInt(c.N),
-			B:       big.NewInt(c.B),
-			Gx:      big.NewInt(c.G.X),
-			Gy:      big.NewInt(c.G.Y),
-			BitSize: c.BS,
+			P:       bc.F.Modulus(),
+			N:       new(big.Int).Set(bc.N),
+			B:       new(big.Int).Set(bc.B),
+			Gx:      new(big.Int).Set(bc.G.X),
+			Gy:      new(big.Int).Set(bc.G.Y),
+			BitSize: bc.BS,
 			Name:    "CompatCurve",
 		},
+		c: bc,
 	}
 
 	return &cc
 }
 
 // Params returns the curve parameters.
+// Note that elliptic.CurveParams assumes that A is -3, use the wrapped
+// curve for the actual parameters.
 func (c *CompatCurve) Params() *elliptic.CurveParams {
 	return c.cp
 }
 
+// toPoint converts affine coordinates to a point. crypto/elliptic uses
+// (0, 0) for the identity element.
+func (c *CompatCurve) toPoint(x, y *big.Int) BigPoint {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return BigPoint{Inf: true}
+	}
+
+	return BigPoint{X: x, Y: y}
+}
+
+func (c *CompatCurve) fromPoint(p BigPoint) (*big.Int, *big.Int) {
+	if p.Inf {
+		return new(big.Int), new(big.Int)
+	}
+
+	return p.X, p.Y
+}
+
 // IsOnCurve returns true if the point is on the curve.
 func (c *CompatCurve) IsOnCurve(x, y *big.Int) bool {
-	return c.cp.IsOnCurve(x, y)
+	return c.c.Valid(BigPoint{X: x, Y: y})
 }
 
 // Add two points on the curve.
 func (c *CompatCurve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
-	return c.cp.Add(x1, y1, x2, y2)
+	return c.fromPoint(c.c.Add(c.toPoint(x1, y1), c.toPoint(x2, y2)))
 }
 
 // Double a single point on the curve.
 func (c *CompatCurve) Double(x, y *big.Int) (*big.Int, *big.Int) {
-	return c.cp.Double(x, y)
+	var p = c.toPoint(x, y)
+
+	return c.fromPoint(c.c.Add(p, p))
 }
 
 // ScalarMult multiplies the point by a scalar.
 func (c *CompatCurve) ScalarMult(x, y *big.Int, k []byte) (*big.Int, *big.Int) {
-	return c.cp.ScalarMult(x, y, k)
+	var kb = new(big.Int).SetBytes(k)
+
+	return c.fromPoint(c.c.ScalarM(kb, c.toPoint(x, y)))
 }
 
 // ScalarBaseMult multiplies the generator point by the scalar.
 func (c *CompatCurve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
-	return c.cp.ScalarBaseMult(k)
+	var kb = new(big.Int).SetBytes(k)
+
+	return c.fromPoint(c.c.ScalarM(kb, c.c.G))
 }
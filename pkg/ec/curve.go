@@ -2,15 +2,12 @@
 package ec
 
 import (
-	"context"
-	"crypto/rand"
 	"fmt"
 	"math"
 	"math/big"
-	"time"
 
 	"github.com/kommendorkapten/sigsim/pkg/field"
-	smath "github.com/kommendorkapten/sigsim/pkg/math"
+	"github.com/kommendorkapten/sigsim/pkg/poly"
 )
 
 var Parallel int64 = 2
@@ -38,6 +35,8 @@ func (p Point) Equal(cmp Point) bool {
 
 // Curve represents an elliptic curve over a finite field satisfying
 // the following equation Y^2 = X^3 + ax + b
+// Curve uses the int64 backed field, the arithmetic is implemented by
+// GenericCurve which also works over big.Int.
 type Curve struct {
 	F  *field.Finite
 	A  int64 // A parameter
@@ -45,6 +44,14 @@ type Curve struct {
 	G  Point // Generator point
 	N  int64 // Order of the generator point
 	BS int   // Bitsize of the underlying field
+	// Strategy selects the scalar multiplication algorithm.
+	Strategy Strategy
+	// Trace, if set, is called for every group operation performed
+	// during scalar multiplication.
+	Trace func(Op)
+	// Window is the window size for wNAF and the fixed-base table. If
+	// zero, DefaultWindow is used.
+	Window int
 }
 
 func (c *Curve) String() string {
@@ -59,7 +66,7 @@ func (c *Curve) String() string {
 }
 
 // DemoCurve25 is a simple curve over a field of bitlength 25.
-// Count points takes 43 seconds
+// CountPointsBG takes 43 seconds
 var DemoCurve25 = &Curve{
 	F: field.NewFinite(33489583),
 	A: -3 + 33489583,
@@ -81,141 +88,101 @@ var DemoCurve63 = &Curve{
 // provided a and b parameters. A _should_ be -3. If the curve's
 // discriminant is zero, an error is returned.
 func NewCurve(f *field.Finite, a, b int64) (*Curve, error) {
-	if !f.Element(a) {
-		return nil, fmt.Errorf(
-			"a: %d is not an element of provided field",
-			a,
-		)
+	var g, err = NewGenericCurve[int64](f, a, b)
+	if err != nil {
+		return nil, err
 	}
 
-	if !f.Element(b) {
-		return nil, fmt.Errorf(
-			"b: %d is not an element of provided field",
-			b,
-		)
+	var c = &Curve{
+		F:  f,
+		A:  g.A,
+		B:  g.B,
+		BS: g.BS,
 	}
 
-	var bs int
-
-	for i := field.BitLength - 1; i > 0; i-- {
-		if (f.P() & (1 << i)) != 0 {
-			bs = i + 1
+	return c, nil
+}
 
-			break
-		}
+// generic returns the curve as a GenericCurve, which implements all
+// the arithmetic.
+func (c *Curve) generic() GenericCurve[int64] {
+	return GenericCurve[int64]{
+		F:  c.F,
+		A:  c.A,
+		B:  c.B,
+		G:  GenericPoint[int64](c.G),
+		N:  c.N,
+		BS: c.BS,
+
+		Strategy: c.Strategy,
+		Trace:    c.Trace,
+		Window:   c.Window,
 	}
+}
 
-	var c = &Curve{
-		F:  f,
-		A:  a,
-		B:  b,
-		BS: bs,
-	}
-	// The discriminant must be non-zero
-	var discriminant = c.Discriminant()
-	if discriminant.BitLen() == 0 {
-		return nil, fmt.Errorf(
-			"provided parameters are not valid, a:%d b:%d",
-			a, b,
-		)
-	}
+// Generic returns the curve as a GenericCurve over the int64 field.
+func (c *Curve) Generic() *GenericCurve[int64] {
+	var g = c.generic()
 
-	return c, nil
+	return &g
+}
+
+// Big returns the curve over a big.Int backed field.
+func (c *Curve) Big() *BigCurve {
+	var g = c.generic()
+
+	return g.Big()
 }
 
 // Discriminant computes the discriminant of the curve.
-// nolint: lll
-// nolint: revive
-// Discriminant is 4a^3 + 27b^2
-// See https://math.stackexchange.com/questions/1653368/discriminant-of-elliptic-curves
-// for more details.
+// See GenericCurve.Discriminant.
 func (c *Curve) Discriminant() *big.Int {
-	var discriminant big.Int
-	var p = big.NewInt(c.A)
-	p.Exp(p, big.NewInt(3), nil)
-	discriminant.Mul(big.NewInt(4), p)
-	p.SetInt64(c.B)
-	p.Exp(p, big.NewInt(2), nil)
-	p.Mul(big.NewInt(27), p)
-	discriminant.Add(&discriminant, p)
+	var g = c.generic()
 
-	return &discriminant
+	return g.Discriminant()
 }
 
 // Verify verifies all the parameters of the curve.
+// See GenericCurve.Verify.
 func (c *Curve) Verify() error {
-	// Is the underlying field a prime
-	var p = big.NewInt(c.F.P())
-	if !p.ProbablyPrime(256) {
-		return fmt.Errorf("field order %d is not prime", c.F.P())
-	}
-
-	if c.Discriminant().BitLen() == 0 {
-		return fmt.Errorf("discriminant is non-zero")
-	}
-
-	// Is generator point on curve
-	if !c.Valid(c.G) {
-		return fmt.Errorf("generator point is not on curve")
-	}
-
-	// Verify order of the point
-	var order = c.Order(c.G)
-	if order != c.N {
-		return fmt.Errorf("invalid order for generator point %d, expected %d",
-			order, c.N)
-	}
+	var g = c.generic()
 
-	p = big.NewInt(c.N)
-	if !p.ProbablyPrime(256) {
-		return fmt.Errorf("curve order %d is not prime", c.N)
-	}
+	return g.Verify()
+}
 
-	// Verify the bitsize of the order
-	if p.BitLen() != c.BS {
-		return fmt.Errorf("bitlength %d for curver order does not match %d",
-			p.BitLen(), c.BS)
-	}
+// VerifyReport verifies all the parameters of the curve, and returns a
+// report with the outcome of each check. See GenericCurve.VerifyReport.
+func (c *Curve) VerifyReport() *Report {
+	var g = c.generic()
 
-	return nil
+	return g.VerifyReport()
 }
 
 // Points calculates and returns all points on the curve.
 // This function can take long time to finish, use with caution.
 func (c *Curve) Points() []Point {
 	var points = map[Point]struct{}{}
-	var canSquareRoot = (c.F.P() % 4) == 3
 
 	// Add the infinity point
 	points[Point{Inf: true}] = struct{}{}
 
 	var x, y int64
 	for x = 0; x < c.F.P(); x++ {
-		if canSquareRoot {
-			var err error
-			if y, err = c.Y(x); err == nil {
-				var p = Point{X: x, Y: y}
-				var valid = c.Valid(p)
-
-				if valid {
-					// both (x, y) and (x, -y) are valid
-					points[p] = struct{}{}
-					if p.Y > 0 {
-						p.Y = -p.Y + c.F.P()
-						points[p] = struct{}{}
-					}
-				}
-			}
+		var err error
+		if y, err = c.Y(x); err != nil {
+			continue
+		}
 
+		var p = Point{X: x, Y: y}
+		if !c.Valid(p) {
 			continue
 		}
 
-		for y = 0; y < c.F.P(); y++ {
-			var p = Point{X: x, Y: y}
-			var valid = c.Valid(p)
-			if valid {
-				points[p] = struct{}{}
-			}
+		// both (x, y) and (x, -y) are valid
+		points[p] = struct{}{}
+		if p.Y > 0 {
+			p.Y = -p.Y + c.F.P()
+			points[p] = struct{}{}
 		}
 	}
 
@@ -227,9 +194,61 @@ func (c *Curve) Points() []Point {
 	return res
 }
 
-// CountPoints using baby-step giant-step
+// CountPointsLegendre counts the points on the curve using the Legendre
+// symbol. For every x, X^3 + ax + b is either zero (one point), a
+// non-zero square (two points) or not a square (no points), so the
+// number of points is p + 1 + sum(legendre(X^3 + ax + b)), where the
+// one accounts for the identity element.
+// No square roots are computed, but the running time is linear in p.
+func (c *Curve) CountPointsLegendre() int64 {
+	var n = c.F.P() + 1
+
+	for x := int64(0); x < c.F.P(); x++ {
+		n += int64(c.F.Legendre(c.rhs(x)))
+	}
+
+	return n
+}
+
+// CountPoints returns the number of points on the curve, computed
+// with Schoof's algorithm. An error is returned if Schoof's algorithm
+// fails, e.g for a singular curve.
+func (c *Curve) CountPoints() (int64, error) {
+	var n, _, err = c.Schoof()
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Schoof counts the points on the curve using Schoof's algorithm.
+// Returned is the number of points n and the trace of Frobenius t,
+// n = p + 1 - t. See GenericCurve.Schoof.
+func (c *Curve) Schoof() (int64, int64, error) {
+	var g = c.generic()
+	var n, t, err = g.Schoof()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return n.Int64(), t.Int64(), nil
+}
+
+// DivisionPolynomial returns the n:th division polynomial,
+// see GenericCurve.DivisionPolynomial.
+func (c *Curve) DivisionPolynomial(n int) poly.Poly[int64] {
+	var g = c.generic()
+
+	return g.DivisionPolynomial(n)
+}
+
+// CountPointsBG using baby-step giant-step
 // https://en.wikipedia.org/wiki/Counting_points_on_elliptic_curves
-func (c *Curve) CountPoints() int64 {
+// The order of random points are computed until only one candidate in
+// the Hasse interval remains. This may never terminate, e.g if the
+// group is not cyclic and its exponent is small. Use CountPoints.
+func (c *Curve) CountPointsBG() int64 {
 	var f = math.Sqrt(float64(c.F.P()))
 	var sq = int64(math.Ceil(f))
 	var nMin = c.F.P() + 1 - 2 * sq
@@ -254,107 +273,25 @@ func (c *Curve) CountPoints() int64 {
 
 // RandomPoint returns a random point on the curve.
 func (c *Curve) RandomPoint() Point {
-	var x *big.Int
-	var p Point
-	var err error
-
-	if (c.F.P() % 4) != 3 {
-		// Can not calculate square root on this prime field.
-		panic(c.F.P())
-	}
-
-	for {
-		x, err = rand.Int(rand.Reader, big.NewInt(c.F.P()))
-		if err != nil {
-			panic(err)
-		}
-		p.X = x.Int64()
-		p.Y, err = c.Y(p.X)
-		if err != nil {
-			continue
-		}
-
-		if c.Valid(p) {
-			return p
-		}
-	}
+	var g = c.generic()
 
+	return Point(g.RandomPoint())
 }
 
 // Y returns the (positive) y coordinate on the curve for a given x
 // coordinate. If x is not on the curve, an error is returned.
 func (c *Curve) Y(x int64) (int64, error) {
-	// Y^2 = X^3 + ax + b
-	var y int64
-	var rhs = c.F.Exponentiate(x, 3)
-	var err error
-
-	rhs = c.F.Add(rhs, c.F.Multiply(c.A, x))
-	rhs = c.F.Add(rhs, c.B)
-
-	y, err = c.F.Sqrt(rhs)
-	if err != nil {
-		var msg = "x: %d does not appear to be on the curve %w"
-
-		return 0, fmt.Errorf(msg, x, err)
-	}
+	var g = c.generic()
 
-	return y, nil
+	return g.Y(x)
 }
 
 // Add two points together and returns the resulting point.
 // If p and q are the some point, p is doubled.
 func (c *Curve) Add(p, q Point) Point {
-	var r Point
-	var m int64
+	var g = c.generic()
 
-	if p.Inf {
-		return q
-	}
-
-	if q.Inf {
-		return p
-	}
-
-	if p.Equal(q) {
-		var inv int64
-		var err error
-
-		m = c.F.Multiply(3, c.F.Multiply(p.X, p.X))
-		m = c.F.Add(m, c.A)
-		inv = c.F.Multiply(2, p.Y)
-
-		inv, err = c.F.Inverse(inv)
-		if err != nil {
-			// infinite slope
-			return Point{Inf: true}
-		}
-
-		m = c.F.Multiply(m, inv)
-	} else {
-		var inv int64
-		var err error
-
-		m = c.F.Add(q.Y, -p.Y)
-		inv = c.F.Add(q.X, -p.X)
-		inv, err = c.F.Inverse(inv)
-		if err != nil {
-			// infinite slope
-			return Point{Inf: true}
-		}
-
-		m = c.F.Multiply(m, inv)
-	}
-
-	r.X = c.F.Multiply(m, m)
-	r.X = c.F.Add(r.X, -p.X)
-	r.X = c.F.Add(r.X, -q.X)
-
-	r.Y = c.F.Add(p.X, -r.X)
-	r.Y = c.F.Multiply(r.Y, m)
-	r.Y = c.F.Add(r.Y, -p.Y)
-
-	return r
+	return Point(g.Add(GenericPoint[int64](p), GenericPoint[int64](q)))
 }
 
 // ScalarM calculates the scalar multiplication of a point.
@@ -362,232 +299,82 @@ func (c *Curve) Add(p, q Point) Point {
 // xP = P' for a known P and P' is the discrete logarithm problem for
 // elliptic curves.
 func (c *Curve) ScalarM(k int64, p Point) Point {
-	var r = Point{Inf: true}
+	var g = c.generic()
 
-	for b := 0; b < field.BitLength; b++ {
-		if (k & int64(1<<b)) != 0 {
-			r = c.Add(r, p)
-		}
-
-		p = c.Add(p, p)
-	}
-
-	return r
+	return Point(g.ScalarM(k, GenericPoint[int64](p)))
 }
 
-// Valid returns true if the provided point is a valid curve point.
-func (c *Curve) Valid(p Point) bool {
-	if p.Inf {
-		return true
-	}
+// ToJacobian converts an affine point to Jacobian coordinates.
+func (c *Curve) ToJacobian(p Point) JacobianPoint[int64] {
+	var g = c.generic()
 
-	var lhs = c.F.Exponentiate(p.Y, 2)
-	var rhs = c.F.Exponentiate(p.X, 3)
-	rhs = c.F.Add(rhs, c.F.Multiply(c.A, p.X))
-	rhs = c.F.Add(rhs, c.B)
-	var valid = lhs == rhs
-
-	return valid
+	return g.ToJacobian(GenericPoint[int64](p))
 }
 
-// Order calculates the order for the provided point.
-// The order is the cardinality of the set of points that which can be
-// reached by multiplying p with a scalar.
-func (c *Curve) Order(p Point) int64 {
-	var order int64
-	var cp Point
-	var pp = p
-
-	start := time.Now()
-	for {
-		order++
+// FromJacobian converts a point in Jacobian coordinates to an affine
+// point.
+func (c *Curve) FromJacobian(p JacobianPoint[int64]) Point {
+	var g = c.generic()
 
-		cp = c.Add(pp, p)
-		if cp == p {
-			// We reached our starting point
-			return order
-		}
-
-		pp = cp
-
-		// 2*P may overflow if P is large.
-		var z = big.NewInt(c.F.P())
-		z.Lsh(z, 2)
-		var o = big.NewInt(order)
-		if o.Cmp(z) > 0 {
-			panic(order)
-		}
-
-		if (order % 1000000) == 0 {
-			dur := time.Since(start)
-			searched := float64(order) / float64(c.F.P())
-			fmt.Printf("Finished %f in %s\n", searched, dur)
-			start = time.Now()
-		}
-	}
-
-	return 0
+	return Point(g.FromJacobian(p))
 }
 
-type job struct{
-	start, stop int64
-	ctx context.Context
-	c chan jobRes
-	pj []Point
-	q Point
-	p Point
-}
+// ToProjective converts an affine point to projective coordinates.
+func (c *Curve) ToProjective(p Point) ProjectivePoint[int64] {
+	var g = c.generic()
 
-type jobRes struct {
-	mp, j int64
+	return g.ToProjective(GenericPoint[int64](p))
 }
 
-// OrderBG computes the order of a point on the curve using
-// Baby-step giant-step
-func (c *Curve) OrderBG(p Point) int64 {
-	// TODO: verify m against the size of the limit imposed by
-	// Hasse's theoereom (seems to be 1/4 of the expected size).
-	var f = math.Sqrt(math.Sqrt(float64(c.F.P())))
-	var m = int64(math.Ceil(f + 0.5)) + 1
-	var pj = make([]Point, m)
-	var q = c.ScalarM(c.F.P() + 1, p)
-	var mp int64 = -1
-
-	// Precompute pj = j * p for j in [0, m]
-	for j := int64(0); j < m; j++ {
-		pj[j] = c.ScalarM(j, p)
-	}
-
-	// Found a point pj that satisfies
-	// (q + k*2*m*p).X = pj.X
-	var chunk = c.F.P() / Parallel
-	var res = make(chan jobRes, 1)
-
-	ctx, stop := context.WithCancel(context.Background())
-
-	for i := int64(0); i < Parallel; i++ {
-		var job = job{
-			start: i * chunk,
-			stop: (i + 1) * chunk,
-			ctx: ctx,
-			c: res,
-			pj: pj,
-			q: q,
-			p: p,
-		}
+// FromProjective converts a point in projective coordinates to an affine
+// point.
+func (c *Curve) FromProjective(p ProjectivePoint[int64]) Point {
+	var g = c.generic()
 
-		// Let the last one do a bit of extra work
-		if i == (Parallel - 1) {
-			job.stop = c.F.P()
-		}
-		go c.approxOrder(&job)
-	}
-
-	var jr = <- res
-	// Got a value, stop all goroutines
-	stop()
+	return Point(g.FromProjective(p))
+}
 
-	// (mp +/- j)*p is now the identity element
-	var id Point
+// MultiScalarM computes k[0]p[0] + k[1]p[1] + ... + k[n-1]p[n-1],
+// see GenericCurve.MultiScalarM.
+func (c *Curve) MultiScalarM(k []int64, p []Point) Point {
+	var g = c.generic()
+	var gp = make([]GenericPoint[int64], len(p))
 
-	id = c.ScalarM(jr.mp + jr.j, p)
-	if id.Inf {
-		mp = jr.mp + jr.j
-	} else {
-		mp = jr.mp - jr.j
+	for i := range p {
+		gp[i] = GenericPoint[int64](p[i])
 	}
 
-	// mp may be a composite, find the minimal value satisfying
-	// mp * p = identity element
-	var pfs = smath.PrimeFactors(mp)
-	for i := 0; i < len(pfs); {
-		var s = mp / pfs[i]
-
-		// Residual is smaller than current prime factor
-		if s == 0 {
-			break
-		}
-
-		id = c.ScalarM(s, p)
-		if id.Inf {
-			mp = s
-		} else {
-			i++
-		}
-	}
-	// mp is now the order of point p
-	return mp
+	return Point(g.MultiScalarM(k, gp))
 }
 
-// This does not work yet...
-func (c *Curve) approxOrder2(job *job) {
-	var m = int64(len(job.pj))
-	var q = c.ScalarM(2*m+1, job.p)
-	var r = job.q
-
-	for i := int64(0); i < m; i++ {
-		var cand = c.Add(r, c.ScalarM(i, q))
+// Valid returns true if the provided point is a valid curve point.
+func (c *Curve) Valid(p Point) bool {
+	var g = c.generic()
 
-		for j := int64(0); j < m; j++ {
-			if cand.X < 0 {
-				fmt.Println("cand")
-			}
-			if job.pj[j].X < 0 {
-				fmt.Println("job")
-			}
-			if cand.X == job.pj[j].X {
-				var mp = c.F.P() + 1 + (2 * m) * i - j
-
-				fmt.Printf("done(%d): found %d %d\n", i, mp, j)
-				// We found a valid point
-				job.c <- jobRes{
-					mp: mp,
-					j: j,
-				}
-				return
-			}
-		}
-	}
+	return g.Valid(GenericPoint[int64](p))
 }
 
-func (c *Curve) approxOrder(job *job) {
-	var cnt = 0
-	var m = int64(len(job.pj))
-
-	var start = time.Now()
-	fmt.Printf("start job %d -> %d\n", job.start, job.stop)
-	for k := job.start; k < job.stop; k++ {
-		var cand Point
+// rhs computes the right hand side of the curve equation,
+// X^3 + ax + b.
+func (c *Curve) rhs(x int64) int64 {
+	var g = c.generic()
 
-		cand = c.Add(job.q, c.ScalarM(k * 2 * m, job.p))
+	return g.rhs(x)
+}
 
-		for j := int64(0); j < m; j++ {
-			if cand.X == job.pj[j].X {
-				var mp = c.F.P() + 1 + (k * 2 * m)
+// Order calculates the order for the provided point.
+// The order is the cardinality of the set of points that which can be
+// reached by multiplying p with a scalar.
+func (c *Curve) Order(p Point) int64 {
+	var g = c.generic()
 
-				fmt.Printf("done(%d): found %d %d\n", k, mp, j)
-				// We found a valid point
-				job.c <- jobRes{
-					mp: mp,
-					j: j,
-				}
-				return
-			}
-		}
+	return g.Order(GenericPoint[int64](p))
+}
 
-		cnt++
-		if (cnt % 100000) == 0 {
-			if job.ctx.Err() != nil {
-				// Context is cancelled
-				return
-			}
+// OrderBG computes the order of a point on the curve using
+// Baby-step giant-step
+func (c *Curve) OrderBG(p Point) int64 {
+	var g = c.generic()
 
-			fmt.Printf("Tried %d points in %s %f\n",
-				cnt,
-				time.Since(start),
-				float32(job.stop - job.start) / float32(cnt),
-			)
-			start = time.Now()
-		}
-	}
+	return g.OrderBG(GenericPoint[int64](p))
 }
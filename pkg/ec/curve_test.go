@@ -49,10 +49,6 @@ func TestNewCurve(t *testing.T) {
 		f  int64
 		bs int
 	}{
-		{
-			f:  5,
-			bs: 3,
-		},
 		{
 			f:  251,
 			bs: 8,
@@ -98,6 +94,11 @@ func TestNewCurve(t *testing.T) {
 		assert.NotNil(t, c)
 		assert.Equal(t, tc.bs, c.BS, "wrong bitsize")
 	}
+
+	// The discriminant is 275 = 5^2 * 11, so the curve is singular
+	// over F_5
+	var _, err = NewCurve(field.NewFinite(5), 2, 3)
+	assert.NotNil(t, err)
 }
 
 func TestCurveAdd(t *testing.T) {
@@ -402,6 +403,45 @@ func TestPointsOnCurve(t *testing.T) {
 	assert.Equal(t, 72, len(got), "wrong number of points")
 }
 
+func TestCountPointsLegendre(t *testing.T) {
+	var tests = []struct {
+		c Curve
+		n int64
+	}{
+		{c: Curve{F: field.NewFinite(5), A: 2, B: 3}, n: 7},
+		{c: Curve{F: field.NewFinite(101), A: 0, B: 73}, n: 102},
+		{c: Curve{F: field.NewFinite(71), A: -1, B: 0}, n: 72},
+		{c: Curve{F: field.NewFinite(263), A: 2, B: 3}, n: 270},
+		{c: Curve{F: field.NewFinite(17), A: 0, B: 7}, n: 18},
+		{c: Curve{F: field.NewFinite(13), A: 1, B: 1}, n: 18},
+	}
+
+	for _, tc := range tests {
+		var c = tc.c
+
+		assert.Equal(t, tc.n, c.CountPointsLegendre(),
+			"wrong number of points for %s", c.String())
+		assert.Equal(t, tc.n, int64(len(c.Points())),
+			"wrong number of points for %s", c.String())
+	}
+}
+
+func TestRandomPoint(t *testing.T) {
+	// Fields not congruent 3 mod 4
+	var tests = []int64{13, 101, 33489593}
+
+	for _, p := range tests {
+		var c, err = NewCurve(field.NewFinite(p), 2, 3)
+		assert.Nil(t, err)
+
+		for i := 0; i < 10; i++ {
+			var r = c.RandomPoint()
+
+			assert.True(t, c.Valid(r), "invalid point %+v", r)
+		}
+	}
+}
+
 func TestOrder(t *testing.T) {
 	t.Parallel()
 	t.Run("curve_263_2_3", func(t *testing.T) {
@@ -0,0 +1,131 @@
+package ec
+
+import (
+	"fmt"
+
+	"github.com/kommendorkapten/sigsim/pkg/poly"
+)
+
+// divisionPolys computes and caches division polynomials.
+// For odd n, psi_n is a polynomial in x only. For even n, psi_n is y
+// times a polynomial in x, and only that polynomial is stored.
+type divisionPolys[E any] struct {
+	c     *GenericCurve[E]
+	f2    poly.Poly[E] // (x^3 + ax + b)^2
+	cache map[int]poly.Poly[E]
+}
+
+func newDivisionPolys[E any](c *GenericCurve[E]) *divisionPolys[E] {
+	var f = c.rhsPoly()
+
+	return &divisionPolys[E]{
+		c:     c,
+		f2:    f.Mul(f),
+		cache: map[int]poly.Poly[E]{},
+	}
+}
+
+// rhsPoly returns x^3 + ax + b as a polynomial.
+func (c *GenericCurve[E]) rhsPoly() poly.Poly[E] {
+	return poly.New(c.F, c.B, c.A, c.F.FromInt64(0), c.F.FromInt64(1))
+}
+
+func (d *divisionPolys[E]) get(n int) poly.Poly[E] {
+	if p, ok := d.cache[n]; ok {
+		return p
+	}
+
+	var f = d.c.F
+	var a = d.c.A
+	var b = d.c.B
+	var res poly.Poly[E]
+
+	// Helper to create constants
+	var k = func(i int64) E {
+		return f.FromInt64(i)
+	}
+
+	switch {
+	case n == 0:
+		res = poly.Zero(f)
+	case n == 1:
+		res = poly.One(f)
+	case n == 2:
+		// psi_2 = 2y
+		res = poly.New(f, k(2))
+	case n == 3:
+		// psi_3 = 3x^4 + 6ax^2 + 12bx - a^2
+		res = poly.New(f,
+			f.Neg(f.Multiply(a, a)),
+			f.Multiply(k(12), b),
+			f.Multiply(k(6), a),
+			k(0),
+			k(3),
+		)
+	case n == 4:
+		// psi_4 = 4y(x^6 + 5ax^4 + 20bx^3 - 5a^2x^2 - 4abx - 8b^2 - a^3)
+		var a2 = f.Multiply(a, a)
+		var c0 = f.Add(f.Multiply(k(-8), f.Multiply(b, b)),
+			f.Neg(f.Multiply(a2, a)))
+
+		res = poly.New(f,
+			c0,
+			f.Multiply(k(-4), f.Multiply(a, b)),
+			f.Multiply(k(-5), a2),
+			f.Multiply(k(20), b),
+			f.Multiply(k(5), a),
+			k(0),
+			k(1),
+		).Scale(k(4))
+	case n%2 == 1:
+		// psi_2m+1 = psi_m+2 * psi_m^3 - psi_m-1 * psi_m+1^3
+		// For even m, the first term contains y^4, otherwise the
+		// second one does.
+		var m = n / 2
+		var t1 = d.get(m + 2).Mul(d.cube(d.get(m)))
+		var t2 = d.get(m - 1).Mul(d.cube(d.get(m + 1)))
+
+		if m%2 == 0 {
+			t1 = t1.Mul(d.f2)
+		} else {
+			t2 = t2.Mul(d.f2)
+		}
+
+		res = t1.Sub(t2)
+	default:
+		// psi_2m = psi_m / 2y * (psi_m+2 * psi_m-1^2 - psi_m-2 * psi_m+1^2)
+		// The y factors cancel out with the y for psi_2m.
+		var m = n / 2
+		var t1 = d.get(m + 2).Mul(d.square(d.get(m - 1)))
+		var t2 = d.get(m - 2).Mul(d.square(d.get(m + 1)))
+		var inv2, _ = f.Inverse(k(2))
+
+		res = d.get(m).Mul(t1.Sub(t2)).Scale(inv2)
+	}
+
+	d.cache[n] = res
+
+	return res
+}
+
+func (d *divisionPolys[E]) square(p poly.Poly[E]) poly.Poly[E] {
+	return p.Mul(p)
+}
+
+func (d *divisionPolys[E]) cube(p poly.Poly[E]) poly.Poly[E] {
+	return p.Mul(p).Mul(p)
+}
+
+// DivisionPolynomial returns the n:th division polynomial psi_n, n >= 0.
+// The roots of psi_n are the x coordinates of the non-zero points P
+// where nP is the identity element.
+// For even n, psi_n contains a factor y, which is removed, i.e
+// psi_n = y * DivisionPolynomial(n). The points of order two are then no
+// longer represented by the roots.
+func (c *GenericCurve[E]) DivisionPolynomial(n int) poly.Poly[E] {
+	if n < 0 {
+		panic(fmt.Sprintf("negative division polynomial index %d", n))
+	}
+
+	return newDivisionPolys(c).get(n)
+}
@@ -0,0 +1,95 @@
+package ec
+
+import (
+	"testing"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDivisionPolynomial(t *testing.T) {
+	var c, err = NewCurve(field.NewFinite(1009), 2, 3)
+	var f = c.F
+
+	assert.Nil(t, err)
+	assert.Equal(t, -1, c.DivisionPolynomial(0).Degree())
+	assert.Equal(t, 0, c.DivisionPolynomial(1).Degree())
+	assert.Equal(t, 4, c.DivisionPolynomial(3).Degree())
+	// (n^2 - 1) / 2 for odd n and (n^2 - 4) / 2 for even n
+	assert.Equal(t, 12, c.DivisionPolynomial(5).Degree())
+	assert.Equal(t, 16, c.DivisionPolynomial(6).Degree())
+
+	// psi_n(P) = y^n * ..., evaluated at a point
+	var psi = func(n int64, p Point) int64 {
+		var v = c.DivisionPolynomial(int(n)).Eval(p.X)
+
+		if n%2 == 0 {
+			v = f.Multiply(v, p.Y)
+		}
+
+		return v
+	}
+
+	// x(nP) = x - psi_n-1 * psi_n+1 / psi_n^2
+	for i := 0; i < 10; i++ {
+		var p = c.RandomPoint()
+
+		for n := int64(2); n < 12; n++ {
+			var q = c.ScalarM(n, p)
+			var d = f.Multiply(psi(n, p), psi(n, p))
+
+			if q.Inf {
+				assert.Equal(t, int64(0), d)
+
+				continue
+			}
+
+			var inv, err = f.Inverse(d)
+			assert.Nil(t, err)
+
+			var x = f.Add(p.X,
+				f.Neg(f.Multiply(f.Multiply(psi(n-1, p), psi(n+1, p)), inv)))
+			assert.Equal(t, q.X, x, "x(%dP) for %v", n, p)
+		}
+	}
+}
+
+func TestDivisionPolynomialRoots(t *testing.T) {
+	// 1020 points
+	var c, err = NewCurve(field.NewFinite(1009), 1, 6)
+	assert.Nil(t, err)
+
+	var n int64
+	n, err = c.CountPoints()
+	assert.Nil(t, err)
+
+	for _, l := range []int64{3, 5, 7} {
+		var psi = c.DivisionPolynomial(int(l))
+		var torsion = map[int64]bool{}
+
+		// Collect the x coordinates for all points of order l
+		if n%l == 0 {
+			for i := 0; i < 100; i++ {
+				var p = c.ScalarM(n/l, c.RandomPoint())
+
+				if !p.Inf {
+					torsion[p.X] = true
+				}
+			}
+		}
+
+		for _, x := range psi.Roots() {
+			var y, err = c.Y(x)
+			if err != nil {
+				// Point is on the quadratic twist
+				continue
+			}
+
+			assert.True(t, torsion[x], "x %d is not l-torsion", x)
+			assert.True(t, c.ScalarM(l, Point{X: x, Y: y}).Inf)
+			delete(torsion, x)
+		}
+
+		assert.Empty(t, torsion, "missing roots for %d", l)
+	}
+}
@@ -0,0 +1,40 @@
+package ec
+
+import (
+	"math/big"
+)
+
+// EmbeddingDegree returns the embedding degree of the curve, the
+// smallest k such that N divides p^k - 1. The Weil and Tate pairings map
+// the subgroup of order N into F_p^k, so if k is small the discrete
+// logarithm problem can be moved to a finite field (the MOV attack).
+// If no k <= max exists, zero is returned.
+func (c *GenericCurve[E]) EmbeddingDegree(max int) int {
+	var n = c.F.Int(c.N)
+
+	if n.Sign() <= 0 {
+		return 0
+	}
+
+	var p = new(big.Int).Mod(c.F.Modulus(), n)
+	var pk = new(big.Int).Set(p)
+
+	for k := 1; k <= max; k++ {
+		if pk.Cmp(one) == 0 {
+			return k
+		}
+
+		pk.Mul(pk, p)
+		pk.Mod(pk, n)
+	}
+
+	return 0
+}
+
+// EmbeddingDegree returns the embedding degree of the curve, see
+// GenericCurve.EmbeddingDegree.
+func (c *Curve) EmbeddingDegree(max int) int {
+	var g = c.generic()
+
+	return g.EmbeddingDegree(max)
+}
@@ -0,0 +1,477 @@
+package ec
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	smath "github.com/kommendorkapten/sigsim/pkg/math"
+)
+
+var one = big.NewInt(1)
+
+// GenericPoint represents a point on a curve over any field backend.
+// If the point is the identity element, Inf is set to true.
+type GenericPoint[E any] struct {
+	X   E
+	Y   E
+	Inf bool
+}
+
+// GenericCurve represents an elliptic curve satisfying the equation
+// Y^2 = X^3 + ax + b over any field backend implementing field.Field.
+// Scalars (and the order N) use the same representation as the field
+// elements, but they are integers and never reduced mod P.
+// Curve is the specialization for the int64 backed field.Finite.
+type GenericCurve[E any] struct {
+	F  field.Field[E]
+	A  E               // A parameter
+	B  E               // B parameter
+	G  GenericPoint[E] // Generator point
+	N  E               // Order of the generator point
+	BS int             // Bitsize of the underlying field
+	// Strategy selects the scalar multiplication algorithm.
+	Strategy Strategy
+	// Trace, if set, is called for every group operation performed
+	// during scalar multiplication.
+	Trace func(Op)
+	// Window is the window size for wNAF and the fixed-base table. If
+	// zero, DefaultWindow is used.
+	Window int
+}
+
+// BigCurve is a curve over a big.Int backed field.
+type BigCurve = GenericCurve[*big.Int]
+
+// BigPoint is a point on a BigCurve.
+type BigPoint = GenericPoint[*big.Int]
+
+// NewGenericCurve returns a Weierstrass curve over the finite field,
+// using the provided a and b parameters. A _should_ be -3. If the
+// curve's discriminant is zero mod p, an error is returned.
+func NewGenericCurve[E any](f field.Field[E], a, b E) (*GenericCurve[E],
+	error) {
+	if !f.Element(a) {
+		return nil, fmt.Errorf(
+			"a: %v is not an element of provided field",
+			a,
+		)
+	}
+
+	if !f.Element(b) {
+		return nil, fmt.Errorf(
+			"b: %v is not an element of provided field",
+			b,
+		)
+	}
+
+	var c = &GenericCurve[E]{
+		F:  f,
+		A:  a,
+		B:  b,
+		BS: f.Modulus().BitLen(),
+	}
+	// The discriminant must be non-zero in the field
+	var discriminant = c.Discriminant()
+	if discriminant.Mod(discriminant, f.Modulus()).Sign() == 0 {
+		return nil, fmt.Errorf(
+			"provided parameters are not valid, a:%v b:%v",
+			a, b,
+		)
+	}
+
+	return c, nil
+}
+
+// Big returns the curve over a big.Int backed field. If c already is a
+// BigCurve, c is returned.
+func (c *GenericCurve[E]) Big() *BigCurve {
+	if bc, ok := any(c).(*BigCurve); ok {
+		return bc
+	}
+
+	return &BigCurve{
+		F:  field.NewBigFinite(c.F.Modulus()),
+		A:  c.F.Int(c.A),
+		B:  c.F.Int(c.B),
+		G:  c.BigPoint(c.G),
+		N:  c.F.Int(c.N),
+		BS: c.BS,
+
+		Strategy: c.Strategy,
+		Trace:    c.Trace,
+		Window:   c.Window,
+	}
+}
+
+// BigPoint converts a point on c to a point on the corresponding
+// BigCurve.
+func (c *GenericCurve[E]) BigPoint(p GenericPoint[E]) BigPoint {
+	if p.Inf {
+		return BigPoint{Inf: true}
+	}
+
+	return BigPoint{
+		X: c.F.Int(p.X),
+		Y: c.F.Int(p.Y),
+	}
+}
+
+func (c *GenericCurve[E]) String() string {
+	return fmt.Sprintf("%d %v %v %+v %v %d",
+		c.F.Modulus(),
+		c.A,
+		c.B,
+		c.G,
+		c.N,
+		c.BS,
+	)
+}
+
+// Discriminant computes the discriminant of the curve.
+// nolint: lll
+// nolint: revive
+// Discriminant is 4a^3 + 27b^2
+// See https://math.stackexchange.com/questions/1653368/discriminant-of-elliptic-curves
+// for more details.
+func (c *GenericCurve[E]) Discriminant() *big.Int {
+	var discriminant big.Int
+	var p = c.F.Int(c.A)
+	p.Exp(p, big.NewInt(3), nil)
+	discriminant.Mul(big.NewInt(4), p)
+	p = c.F.Int(c.B)
+	p.Exp(p, big.NewInt(2), nil)
+	p.Mul(big.NewInt(27), p)
+	discriminant.Add(&discriminant, p)
+
+	return &discriminant
+}
+
+// RandomPoint returns a random point on the curve.
+func (c *GenericCurve[E]) RandomPoint() GenericPoint[E] {
+	var x *big.Int
+	var p GenericPoint[E]
+	var err error
+
+	for {
+		x, err = rand.Int(rand.Reader, c.F.Modulus())
+		if err != nil {
+			panic(err)
+		}
+		p.X = c.F.FromInt(x)
+		p.Y, err = c.Y(p.X)
+		if err != nil {
+			continue
+		}
+
+		if c.Valid(p) {
+			return p
+		}
+	}
+}
+
+// Y returns the (positive) y coordinate on the curve for a given x
+// coordinate. If x is not on the curve, an error is returned.
+func (c *GenericCurve[E]) Y(x E) (E, error) {
+	var y E
+	var rhs = c.rhs(x)
+	var err error
+
+	// Test for a quadratic residue first, it's cheaper than Sqrt.
+	if c.F.Legendre(rhs) == -1 {
+		var msg = "x: %v does not appear to be on the curve, " +
+			"%v is not a square"
+
+		return y, fmt.Errorf(msg, x, rhs)
+	}
+
+	y, err = c.F.Sqrt(rhs)
+	if err != nil {
+		var msg = "x: %v does not appear to be on the curve %w"
+
+		return y, fmt.Errorf(msg, x, err)
+	}
+
+	return y, nil
+}
+
+// Equal test if two points are equal.
+func (c *GenericCurve[E]) Equal(p, q GenericPoint[E]) bool {
+	if p.Inf || q.Inf {
+		return p.Inf == q.Inf
+	}
+
+	return c.F.Equal(p.X, q.X) && c.F.Equal(p.Y, q.Y)
+}
+
+// Neg returns the inverse of p, i.e (X, -Y).
+func (c *GenericCurve[E]) Neg(p GenericPoint[E]) GenericPoint[E] {
+	if p.Inf {
+		return p
+	}
+
+	return GenericPoint[E]{
+		X: p.X,
+		Y: c.F.Neg(p.Y),
+	}
+}
+
+// Add two points together and returns the resulting point.
+// If p and q are the some point, p is doubled.
+func (c *GenericCurve[E]) Add(p, q GenericPoint[E]) GenericPoint[E] {
+	var r GenericPoint[E]
+	var m E
+	var inv E
+	var err error
+
+	if p.Inf {
+		return q
+	}
+
+	if q.Inf {
+		return p
+	}
+
+	if c.Equal(p, q) {
+		m = c.F.Multiply(c.F.FromInt64(3), c.F.Multiply(p.X, p.X))
+		m = c.F.Add(m, c.A)
+		inv = c.F.Multiply(c.F.FromInt64(2), p.Y)
+	} else {
+		m = c.F.Add(q.Y, c.F.Neg(p.Y))
+		inv = c.F.Add(q.X, c.F.Neg(p.X))
+	}
+
+	inv, err = c.F.Inverse(inv)
+	if err != nil {
+		// infinite slope
+		return GenericPoint[E]{Inf: true}
+	}
+
+	m = c.F.Multiply(m, inv)
+
+	r.X = c.F.Multiply(m, m)
+	r.X = c.F.Add(r.X, c.F.Neg(p.X))
+	r.X = c.F.Add(r.X, c.F.Neg(q.X))
+
+	r.Y = c.F.Add(p.X, c.F.Neg(r.X))
+	r.Y = c.F.Multiply(r.Y, m)
+	r.Y = c.F.Add(r.Y, c.F.Neg(p.Y))
+
+	return r
+}
+
+// scalarMAffine is ScalarM using affine coordinates, i.e with an
+// inversion for every addition and doubling.
+func (c *GenericCurve[E]) scalarMAffine(k E,
+	p GenericPoint[E]) GenericPoint[E] {
+	var r = GenericPoint[E]{Inf: true}
+	var kb = c.F.Int(k)
+
+	if kb.Sign() < 0 {
+		kb.Neg(kb)
+		p = c.Neg(p)
+	}
+
+	for b := 0; b < kb.BitLen(); b++ {
+		if kb.Bit(b) != 0 {
+			r = c.Add(r, p)
+		}
+
+		p = c.Add(p, p)
+	}
+
+	return r
+}
+
+// Valid returns true if the provided point is a valid curve point.
+// The coordinates must be canonical field elements, i.e in [0, p).
+func (c *GenericCurve[E]) Valid(p GenericPoint[E]) bool {
+	if p.Inf {
+		return true
+	}
+
+	if !c.F.Element(p.X) || !c.F.Element(p.Y) {
+		return false
+	}
+
+	var lhs = c.F.Multiply(p.Y, p.Y)
+	var rhs = c.rhs(p.X)
+
+	return c.F.Equal(lhs, rhs)
+}
+
+// rhs computes the right hand side of the curve equation,
+// X^3 + ax + b.
+func (c *GenericCurve[E]) rhs(x E) E {
+	var r = c.F.Multiply(x, c.F.Multiply(x, x))
+
+	r = c.F.Add(r, c.F.Multiply(c.A, x))
+
+	return c.F.Add(r, c.B)
+}
+
+// Order calculates the order for the provided point.
+// The order is the cardinality of the set of points that which can be
+// reached by multiplying p with a scalar.
+func (c *GenericCurve[E]) Order(p GenericPoint[E]) E {
+	var order int64
+	var cp GenericPoint[E]
+	var pp = p
+	// 2*P may overflow if P is large.
+	var z = c.F.Modulus()
+	z.Lsh(z, 2)
+
+	for {
+		order++
+
+		cp = c.Add(pp, p)
+		if c.Equal(cp, p) {
+			// We reached our starting point
+			return c.F.FromInt(big.NewInt(order))
+		}
+
+		pp = cp
+
+		var o = big.NewInt(order)
+		if o.Cmp(z) > 0 {
+			panic(order)
+		}
+	}
+}
+
+// sameX returns true if p and q have the same X coordinate, that is if
+// p = +/- q.
+func (c *GenericCurve[E]) sameX(p, q GenericPoint[E]) bool {
+	if p.Inf || q.Inf {
+		return p.Inf == q.Inf
+	}
+
+	return c.F.Equal(p.X, q.X)
+}
+
+type job[E any] struct {
+	start, stop *big.Int
+	ctx         context.Context
+	c           chan jobRes
+	pj          []GenericPoint[E]
+	q           GenericPoint[E]
+	p           GenericPoint[E]
+}
+
+type jobRes struct {
+	mp, j *big.Int
+}
+
+// OrderBG computes the order of a point on the curve using
+// Baby-step giant-step
+func (c *GenericCurve[E]) OrderBG(p GenericPoint[E]) E {
+	// TODO: verify m against the size of the limit imposed by
+	// Hasse's theoereom (seems to be 1/4 of the expected size).
+	var fp = c.F.Modulus()
+	var f, _ = new(big.Float).SetInt(fp).Float64()
+	var m = int64(math.Ceil(math.Sqrt(math.Sqrt(f))+0.5)) + 1
+	var pj = make([]GenericPoint[E], m)
+	var q = c.ScalarM(c.F.FromInt(new(big.Int).Add(fp, one)), p)
+	var mp *big.Int
+
+	// Precompute pj = j * p for j in [0, m]
+	for j := int64(0); j < m; j++ {
+		pj[j] = c.ScalarM(c.F.FromInt(big.NewInt(j)), p)
+	}
+
+	// Found a point pj that satisfies
+	// (q + k*2*m*p).X = pj.X
+	var chunk = new(big.Int).Div(fp, big.NewInt(Parallel))
+	var res = make(chan jobRes, 1)
+
+	ctx, stop := context.WithCancel(context.Background())
+
+	for i := int64(0); i < Parallel; i++ {
+		var job = job[E]{
+			start: new(big.Int).Mul(big.NewInt(i), chunk),
+			stop:  new(big.Int).Mul(big.NewInt(i+1), chunk),
+			ctx:   ctx,
+			c:     res,
+			pj:    pj,
+			q:     q,
+			p:     p,
+		}
+
+		// Let the last one do a bit of extra work
+		if i == (Parallel - 1) {
+			job.stop = fp
+		}
+		go c.approxOrder(&job)
+	}
+
+	var jr = <-res
+	// Got a value, stop all goroutines
+	stop()
+
+	// (mp +/- j)*p is now the identity element
+	var id GenericPoint[E]
+
+	mp = new(big.Int).Add(jr.mp, jr.j)
+	id = c.ScalarM(c.F.FromInt(mp), p)
+	if !id.Inf {
+		mp.Sub(jr.mp, jr.j)
+	}
+
+	// mp may be a composite, find the minimal value satisfying
+	// mp * p = identity element
+	var pfs = smath.PrimeFactorsBig(mp)
+	for i := 0; i < len(pfs); {
+		var s = new(big.Int).Div(mp, pfs[i])
+
+		// Residual is smaller than current prime factor
+		if s.Sign() == 0 {
+			break
+		}
+
+		id = c.ScalarM(c.F.FromInt(s), p)
+		if id.Inf {
+			mp = s
+		} else {
+			i++
+		}
+	}
+	// mp is now the order of point p
+	return c.F.FromInt(mp)
+}
+
+func (c *GenericCurve[E]) approxOrder(job *job[E]) {
+	var cnt = 0
+	var m = int64(len(job.pj))
+	var step = big.NewInt(2 * m)
+	var fp = c.F.Modulus()
+
+	for k := new(big.Int).Set(job.start); k.Cmp(job.stop) < 0; k.Add(k, one) {
+		var cand GenericPoint[E]
+		var km = new(big.Int).Mul(k, step)
+
+		cand = c.Add(job.q, c.ScalarM(c.F.FromInt(km), job.p))
+
+		for j := int64(0); j < m; j++ {
+			if c.sameX(cand, job.pj[j]) {
+				var mp = new(big.Int).Add(fp, one)
+				mp.Add(mp, km)
+
+				// We found a valid point
+				job.c <- jobRes{
+					mp: mp,
+					j:  big.NewInt(j),
+				}
+				return
+			}
+		}
+
+		cnt++
+		if (cnt%100000) == 0 && job.ctx.Err() != nil {
+			// Context is cancelled
+			return
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package ec
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenericMatchesCurve(t *testing.T) {
+	var c = &Curve{
+		F: field.NewFinite(263),
+		A: 2,
+		B: 3,
+	}
+	var gc = c.Generic()
+	var bc = c.Big()
+	var g = Point{X: 200, Y: 39}
+	var bg = gc.BigPoint(GenericPoint[int64](g))
+
+	assert.True(t, bc.Valid(bg))
+
+	var acc = Point{Inf: true}
+	var bacc = BigPoint{Inf: true}
+	for k := int64(0); k < 280; k++ {
+		var r = c.ScalarM(k, g)
+		var br = bc.ScalarM(big.NewInt(k), bg)
+
+		assert.True(t, bc.Equal(gc.BigPoint(GenericPoint[int64](r)), br),
+			"%d*G", k)
+		assert.True(t, bc.Equal(gc.BigPoint(GenericPoint[int64](acc)), bacc),
+			"%d*G", k)
+		assert.True(t, bc.Valid(br))
+
+		acc = c.Add(acc, g)
+		bacc = bc.Add(bacc, bg)
+	}
+
+	assert.Equal(t, int64(270), bc.Order(bg).Int64())
+	assert.Equal(t, int64(270), bc.OrderBG(bg).Int64())
+
+	// Negative scalars
+	var r = bc.ScalarM(big.NewInt(-1), bg)
+	assert.True(t, bc.Equal(bc.Neg(bg), r))
+	assert.True(t, bc.Add(r, bg).Inf)
+}
+
+func TestNewGenericCurve(t *testing.T) {
+	var f = field.NewBigFinite(big.NewInt(479))
+	var c, err = NewGenericCurve[*big.Int](f,
+		big.NewInt(476),
+		big.NewInt(307))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 9, c.BS)
+
+	c.G = BigPoint{X: big.NewInt(403), Y: big.NewInt(280)}
+	c.N = big.NewInt(233)
+	c.BS = 8
+	assert.Nil(t, c.Verify())
+
+	_, err = NewGenericCurve[*big.Int](f, big.NewInt(479), big.NewInt(1))
+	assert.NotNil(t, err)
+
+	// y^2 = x^3 - 3x + 2 is singular, the discriminant is only zero
+	// mod p
+	_, err = NewCurve(field.NewFinite(23), 20, 2)
+	assert.NotNil(t, err)
+}
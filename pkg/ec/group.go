@@ -0,0 +1,264 @@
+package ec
+
+import (
+	"fmt"
+	"math/big"
+
+	smath "github.com/kommendorkapten/sigsim/pkg/math"
+)
+
+// GroupStructure describes the group of points on a curve. The group
+// is isomorphic to Z/N1 x Z/N2 where N2 divides N1 (and p - 1). If N2
+// is one, the group is cyclic.
+type GroupStructure struct {
+	// N is the number of points on the curve, N = N1 * N2.
+	N  int64
+	N1 int64
+	N2 int64
+	// P1 generates the Z/N1 factor and P2 the Z/N2 factor, so every
+	// point can be uniquely written as aP1 + bP2.
+	P1 Point
+	P2 Point
+	// Cofactor is N divided by the largest prime factor of N, i.e the
+	// cofactor of the largest prime order subgroup.
+	Cofactor int64
+}
+
+// Cyclic returns true if the group is cyclic, i.e generated by P1.
+func (g *GroupStructure) Cyclic() bool {
+	return g.N2 == 1
+}
+
+// GenericGroupStructure describes the group of points on a
+// GenericCurve, see GroupStructure.
+type GenericGroupStructure[E any] struct {
+	N        *big.Int
+	N1       *big.Int
+	N2       *big.Int
+	P1       GenericPoint[E]
+	P2       GenericPoint[E]
+	Cofactor *big.Int
+}
+
+// Cyclic returns true if the group is cyclic, i.e generated by P1.
+func (g *GenericGroupStructure[E]) Cyclic() bool {
+	return g.N2.Cmp(one) == 0
+}
+
+// maxSylowTries is the number of random points tried when searching
+// for a basis of a Sylow subgroup.
+const maxSylowTries = 1000
+
+// GroupStructure computes the structure of the group of points on the
+// curve, see GenericCurve.GroupStructure.
+func (c *Curve) GroupStructure() (*GroupStructure, error) {
+	var g = c.generic()
+	var gs, err = g.GroupStructure()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GroupStructure{
+		N:        gs.N.Int64(),
+		N1:       gs.N1.Int64(),
+		N2:       gs.N2.Int64(),
+		P1:       Point(gs.P1),
+		P2:       Point(gs.P2),
+		Cofactor: gs.Cofactor.Int64(),
+	}, nil
+}
+
+// GroupStructure computes the structure of the group of points on the
+// curve. The number of points is computed with Schoof's algorithm, and
+// an error is returned if that fails, e.g for a singular curve.
+// The group is the direct sum of its Sylow subgroups, which each are of
+// the form Z/l^a x Z/l^b. A basis is found for each of the Sylow
+// subgroups using random points, and the bases are then combined. An
+// error is returned if no basis is found after maxSylowTries points.
+func (c *GenericCurve[E]) GroupStructure() (*GenericGroupStructure[E],
+	error) {
+	var n, _, err = c.Schoof()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.groupStructure(n)
+}
+
+// groupStructure computes the group structure, given the number of
+// points n.
+func (c *GenericCurve[E]) groupStructure(
+	n *big.Int) (*GenericGroupStructure[E], error) {
+	var pfs = smath.PrimeFactorsBig(n)
+	var gs = GenericGroupStructure[E]{
+		N:        n,
+		N1:       big.NewInt(1),
+		N2:       big.NewInt(1),
+		P1:       GenericPoint[E]{Inf: true},
+		P2:       GenericPoint[E]{Inf: true},
+		Cofactor: new(big.Int).Set(n),
+	}
+
+	if len(pfs) > 0 {
+		gs.Cofactor.Div(n, pfs[len(pfs)-1])
+	}
+
+	for i := 0; i < len(pfs); {
+		var l = pfs[i]
+		var e = 0
+
+		for ; i < len(pfs) && pfs[i].Cmp(l) == 0; i++ {
+			e++
+		}
+
+		var p1, a, p2, b, err = c.sylowBasis(n, l, e)
+		if err != nil {
+			return nil, err
+		}
+
+		gs.N1.Mul(gs.N1, pow(l, a))
+		gs.N2.Mul(gs.N2, pow(l, b))
+		gs.P1 = c.Add(gs.P1, p1)
+		gs.P2 = c.Add(gs.P2, p2)
+	}
+
+	return &gs, nil
+}
+
+// sylowBasis returns a basis for the l-Sylow subgroup, with points p1
+// of order l^a and p2 of order l^b, where a >= b and a + b = e.
+// n is the number of points on the curve, and l^e divides n exactly.
+// If n is not the number of points, a basis may never be found, so at
+// most maxSylowTries random points are tried.
+func (c *GenericCurve[E]) sylowBasis(n, l *big.Int,
+	e int) (GenericPoint[E], int, GenericPoint[E], int, error) {
+	var h = new(big.Int).Div(n, pow(l, e))
+	var p1 = GenericPoint[E]{Inf: true}
+	var a = 0
+
+	for i := 0; i < maxSylowTries; i++ {
+		// Random point in the Sylow subgroup
+		var r = c.scalarMInt(h, c.RandomPoint())
+		var o = c.lOrder(r, l)
+
+		if o > a {
+			p1, a = r, o
+		}
+
+		if a == e {
+			return p1, a, GenericPoint[E]{Inf: true}, 0, nil
+		}
+
+		if o == 0 {
+			continue
+		}
+
+		var b = e - a
+		if b > a {
+			// p1 is not of maximal order
+			continue
+		}
+
+		// Remove the p1 component from r. l^b * r is in <l^b * p1>,
+		// as l^b kills the Z/l^b factor.
+		var lb = pow(l, b)
+		var x, ok = c.dlogBG(c.scalarMInt(lb, p1), c.scalarMInt(lb, r),
+			pow(l, a-b))
+		if !ok {
+			continue
+		}
+
+		var q = c.Add(r, c.Neg(c.scalarMInt(x, p1)))
+		// q is of order l^b and independent of p1 iff
+		// l^(b-1) * q is not in the order l subgroup of <p1>.
+		var u = c.scalarMInt(pow(l, b-1), q)
+		if u.Inf {
+			continue
+		}
+
+		if _, ok = c.dlogBG(c.scalarMInt(pow(l, a-1), p1), u, l); ok {
+			continue
+		}
+
+		return p1, a, q, b, nil
+	}
+
+	var inf = GenericPoint[E]{Inf: true}
+
+	return inf, 0, inf, 0, fmt.Errorf("no basis found for the %s-Sylow "+
+		"subgroup of order %s", l, pow(l, e))
+}
+
+// scalarMInt returns kp for an integer k.
+func (c *GenericCurve[E]) scalarMInt(k *big.Int,
+	p GenericPoint[E]) GenericPoint[E] {
+	return c.ScalarM(c.F.FromInt(k), p)
+}
+
+// lOrder returns k where the order of p is l^k. The order of p must be
+// a power of l.
+func (c *GenericCurve[E]) lOrder(p GenericPoint[E], l *big.Int) int {
+	var k = 0
+
+	for ; !p.Inf; k++ {
+		p = c.scalarMInt(l, p)
+	}
+
+	return k
+}
+
+// pointKey returns a representation of p which can be used as a map
+// key, as the coordinates may be pointers.
+func (c *GenericCurve[E]) pointKey(p GenericPoint[E]) string {
+	if p.Inf {
+		return "inf"
+	}
+
+	return c.F.Int(p.X).String() + "," + c.F.Int(p.Y).String()
+}
+
+// dlogBG solves the discrete logarithm q = xp using baby-step
+// giant-step, where n is the order of p. If q is not a multiple of p,
+// false is returned.
+func (c *GenericCurve[E]) dlogBG(p, q GenericPoint[E],
+	n *big.Int) (*big.Int, bool) {
+	var m = new(big.Int).Sqrt(n)
+	if new(big.Int).Mul(m, m).Cmp(n) < 0 {
+		m.Add(m, one)
+	}
+
+	var baby = make(map[string]int64, m.Int64())
+	var jp = GenericPoint[E]{Inf: true}
+
+	// Baby steps, j * p for j in [0, m)
+	for j := int64(0); j < m.Int64(); j++ {
+		var k = c.pointKey(jp)
+
+		if _, ok := baby[k]; !ok {
+			baby[k] = j
+		}
+		jp = c.Add(jp, p)
+	}
+
+	// Giant steps, q - i * m * p
+	var mp = c.Neg(c.scalarMInt(m, p))
+	var g = q
+
+	for i := int64(0); i <= m.Int64(); i++ {
+		if j, ok := baby[c.pointKey(g)]; ok {
+			var x = new(big.Int).Mul(big.NewInt(i), m)
+
+			x.Add(x, big.NewInt(j))
+
+			return x.Mod(x, n), true
+		}
+		g = c.Add(g, mp)
+	}
+
+	return nil, false
+}
+
+// pow returns b^e.
+func pow(b *big.Int, e int) *big.Int {
+	return new(big.Int).Exp(b, big.NewInt(int64(e)), nil)
+}
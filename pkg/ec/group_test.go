@@ -0,0 +1,128 @@
+package ec
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupStructure(t *testing.T) {
+	var tests = []struct {
+		c      Curve
+		n1, n2 int64
+	}{
+		// Full 2-torsion, x^3 - x = x(x - 1)(x + 1)
+		{c: Curve{F: field.NewFinite(71), A: 70, B: 0}, n1: 36, n2: 2},
+		{c: Curve{F: field.NewFinite(13), A: 1, B: 1}, n1: 18, n2: 1},
+		{c: Curve{F: field.NewFinite(101), A: 0, B: 73}, n1: 102, n2: 1},
+		{c: Curve{F: field.NewFinite(263), A: 2, B: 3}, n1: 270, n2: 1},
+	}
+
+	for _, tc := range tests {
+		var c = tc.c
+		var gs, err = c.GroupStructure()
+		assert.Nil(t, err)
+
+		assert.Equal(t, tc.n1, gs.N1, "N1 for %s", c.String())
+		assert.Equal(t, tc.n2, gs.N2, "N2 for %s", c.String())
+		assert.Equal(t, tc.n2 == 1, gs.Cyclic())
+		assertGroup(t, &c, gs)
+	}
+}
+
+func TestGroupStructureSmallCurves(t *testing.T) {
+	for _, p := range []int64{5, 7, 11, 13, 17, 19, 23, 29, 31, 37} {
+		for a := int64(0); a < p; a++ {
+			for b := int64(0); b < p; b++ {
+				var c = Curve{F: field.NewFinite(p), A: a, B: b}
+				var d = c.Discriminant()
+
+				if d.Mod(d, big.NewInt(p)).Sign() == 0 {
+					continue
+				}
+
+				var gs, err = c.GroupStructure()
+				assert.Nil(t, err)
+
+				assert.Equal(t, c.CountPointsLegendre(), gs.N)
+				assertGroup(t, &c, gs)
+			}
+		}
+	}
+}
+
+func TestGroupStructureBig(t *testing.T) {
+	// Full 2-torsion, over a big.Int field
+	var c = (&Curve{F: field.NewFinite(71), A: 70, B: 0}).Big()
+	var gs, err = c.GroupStructure()
+	assert.Nil(t, err)
+
+	assert.Equal(t, big.NewInt(36), gs.N1)
+	assert.Equal(t, big.NewInt(2), gs.N2)
+	assert.Equal(t, big.NewInt(24), gs.Cofactor)
+	assert.False(t, gs.Cyclic())
+	assert.True(t, c.ScalarM(gs.N1, gs.P1).Inf)
+	assert.True(t, c.ScalarM(gs.N2, gs.P2).Inf)
+	assert.False(t, c.ScalarM(big.NewInt(18), gs.P1).Inf)
+	assert.False(t, gs.P2.Inf)
+
+	// Schoof fails for singular curves
+	c = (&Curve{F: field.NewFinite(23), A: 20, B: 2}).Big()
+	_, err = c.GroupStructure()
+	assert.NotNil(t, err)
+}
+
+func TestGroupStructureP256(t *testing.T) {
+	// The number of points is too large to count, but it's known and
+	// prime so the order can be factored
+	var gs, err = P256.groupStructure(P256.N)
+	assert.Nil(t, err)
+
+	assert.True(t, gs.Cyclic())
+	assert.Equal(t, P256.N, gs.N1)
+	assert.True(t, P256.ScalarM(gs.N1, gs.P1).Inf)
+	assert.False(t, gs.P1.Inf)
+}
+
+func TestGroupStructureSingular(t *testing.T) {
+	var c = Curve{F: field.NewFinite(23), A: 20, B: 2}
+	var _, err = c.GroupStructure()
+	assert.ErrorContains(t, err, "singular")
+
+	// The basis search gives up for a wrong number of points, here
+	// the 3-Sylow subgroup is trivial
+	c = Curve{F: field.NewFinite(263), A: 2, B: 3}
+	var g = c.generic()
+	_, err = g.groupStructure(big.NewInt(270 * 3))
+	assert.ErrorContains(t, err, "no basis")
+}
+
+// assertGroup verifies that P1 and P2 generate the group.
+func assertGroup(t *testing.T, c *Curve, gs *GroupStructure) {
+	t.Helper()
+
+	var points = map[Point]bool{}
+
+	assert.Equal(t, gs.N, gs.N1*gs.N2)
+	assert.Equal(t, int64(0), gs.N1%gs.N2)
+	assert.Equal(t, int64(0), (c.F.P()-1)%gs.N2)
+
+	// All aP1 + bP2 must be distinct
+	var ap = Point{Inf: true}
+	for a := int64(0); a < gs.N1; a++ {
+		var q = ap
+
+		for b := int64(0); b < gs.N2; b++ {
+			points[q] = true
+			q = c.Add(q, gs.P2)
+		}
+		// N2 * P2 is the identity
+		assert.True(t, q.Equal(ap), "P2 has wrong order")
+		ap = c.Add(ap, gs.P1)
+	}
+
+	assert.True(t, ap.Inf, "P1 has wrong order")
+	assert.Equal(t, gs.N, int64(len(points)), "P1 and P2 are dependent")
+}
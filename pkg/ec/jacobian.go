@@ -0,0 +1,284 @@
+package ec
+
+// This file implements inversion free point arithmetic. A field
+// inversion is far more expensive than a multiplication, so by
+// representing points with an extra Z coordinate the inversion can be
+// postponed until the result is converted back to affine coordinates.
+// The formulas are from the Explicit-Formulas Database:
+// https://hyperelliptic.org/EFD/g1p/auto-shortw.html
+
+// JacobianPoint is a point in Jacobian coordinates, representing the
+// affine point (X/Z^2, Y/Z^3). If Z is zero the point is the identity
+// element.
+type JacobianPoint[E any] struct {
+	X E
+	Y E
+	Z E
+}
+
+// ProjectivePoint is a point in (homogeneous) projective coordinates,
+// representing the affine point (X/Z, Y/Z). If Z is zero the point is
+// the identity element.
+type ProjectivePoint[E any] struct {
+	X E
+	Y E
+	Z E
+}
+
+// sub returns a - b.
+func (c *GenericCurve[E]) sub(a, b E) E {
+	return c.F.Add(a, c.F.Neg(b))
+}
+
+func (c *GenericCurve[E]) isZero(a E) bool {
+	return c.F.Equal(a, c.F.FromInt64(0))
+}
+
+func (c *GenericCurve[E]) isOne(a E) bool {
+	return c.F.Equal(a, c.F.FromInt64(1))
+}
+
+// minus3 returns true if the curve parameter a is -3, which allows for
+// faster doubling.
+func (c *GenericCurve[E]) minus3() bool {
+	return c.F.Equal(c.F.Canonicalize(c.A), c.F.FromInt64(-3))
+}
+
+// ToJacobian converts an affine point to Jacobian coordinates.
+func (c *GenericCurve[E]) ToJacobian(p GenericPoint[E]) JacobianPoint[E] {
+	if p.Inf {
+		return JacobianPoint[E]{
+			X: c.F.FromInt64(1),
+			Y: c.F.FromInt64(1),
+			Z: c.F.FromInt64(0),
+		}
+	}
+
+	return JacobianPoint[E]{
+		X: c.F.Canonicalize(p.X),
+		Y: c.F.Canonicalize(p.Y),
+		Z: c.F.FromInt64(1),
+	}
+}
+
+// FromJacobian converts a point in Jacobian coordinates to affine
+// coordinates.
+func (c *GenericCurve[E]) FromJacobian(p JacobianPoint[E]) GenericPoint[E] {
+	var zi, err = c.F.Inverse(p.Z)
+	if err != nil {
+		return GenericPoint[E]{Inf: true}
+	}
+
+	var zi2 = c.F.Multiply(zi, zi)
+
+	return GenericPoint[E]{
+		X: c.F.Multiply(p.X, zi2),
+		Y: c.F.Multiply(p.Y, c.F.Multiply(zi2, zi)),
+	}
+}
+
+// DoubleJacobian returns 2p.
+func (c *GenericCurve[E]) DoubleJacobian(p JacobianPoint[E]) JacobianPoint[E] {
+	var f = c.F
+
+	if c.isZero(p.Z) || c.isZero(p.Y) {
+		return c.ToJacobian(GenericPoint[E]{Inf: true})
+	}
+
+	// dbl-2007-bl with the multiplications by small constants
+	// replaced with additions.
+	var yy = f.Multiply(p.Y, p.Y)
+	var yyyy = f.Multiply(yy, yy)
+	var zz = f.Multiply(p.Z, p.Z)
+	var s = f.Multiply(p.X, yy)
+	var m E
+	var r JacobianPoint[E]
+
+	s = f.Add(s, s)
+	s = f.Add(s, s)
+	if c.minus3() {
+		// dbl-2001-b, 3X^2 - 3Z^4 = 3(X - Z^2)(X + Z^2)
+		m = f.Multiply(c.sub(p.X, zz), f.Add(p.X, zz))
+		m = f.Add(f.Add(m, m), m)
+	} else {
+		var xx = f.Multiply(p.X, p.X)
+
+		m = f.Add(f.Add(xx, xx), xx)
+		m = f.Add(m, f.Multiply(c.A, f.Multiply(zz, zz)))
+	}
+
+	yyyy = f.Add(yyyy, yyyy)
+	yyyy = f.Add(yyyy, yyyy)
+	yyyy = f.Add(yyyy, yyyy)
+
+	r.X = c.sub(f.Multiply(m, m), f.Add(s, s))
+	r.Y = c.sub(f.Multiply(m, c.sub(s, r.X)), yyyy)
+	r.Z = f.Multiply(p.Y, p.Z)
+	r.Z = f.Add(r.Z, r.Z)
+
+	return r
+}
+
+// AddJacobian returns p + q.
+func (c *GenericCurve[E]) AddJacobian(p,
+	q JacobianPoint[E]) JacobianPoint[E] {
+	var f = c.F
+
+	if c.isZero(p.Z) {
+		return q
+	}
+
+	if c.isZero(q.Z) {
+		return p
+	}
+
+	// add-2007-bl
+	var z1z1 = f.Multiply(p.Z, p.Z)
+	var z2z2 = q.Z
+	var u1 = p.X
+	var s1 = p.Y
+	var u2 = f.Multiply(q.X, z1z1)
+	var s2 = f.Multiply(q.Y, f.Multiply(p.Z, z1z1))
+
+	// If q is affine (Z = 1), the multiplications by Z2 can be skipped
+	// (madd-2007-bl).
+	if !c.isOne(q.Z) {
+		z2z2 = f.Multiply(q.Z, q.Z)
+		u1 = f.Multiply(p.X, z2z2)
+		s1 = f.Multiply(p.Y, f.Multiply(q.Z, z2z2))
+	}
+	var h = c.sub(u2, u1)
+	var rr = c.sub(s2, s1)
+
+	if c.isZero(h) {
+		if c.isZero(rr) {
+			return c.DoubleJacobian(p)
+		}
+
+		// p = -q
+		return c.ToJacobian(GenericPoint[E]{Inf: true})
+	}
+
+	rr = f.Add(rr, rr)
+
+	var i = f.Add(h, h)
+	i = f.Multiply(i, i)
+	var j = f.Multiply(h, i)
+	var v = f.Multiply(u1, i)
+	var r JacobianPoint[E]
+
+	r.X = c.sub(c.sub(f.Multiply(rr, rr), j), f.Add(v, v))
+	r.Y = f.Multiply(s1, j)
+	r.Y = c.sub(f.Multiply(rr, c.sub(v, r.X)), f.Add(r.Y, r.Y))
+	r.Z = f.Add(p.Z, q.Z)
+	r.Z = c.sub(c.sub(f.Multiply(r.Z, r.Z), z1z1), z2z2)
+	r.Z = f.Multiply(r.Z, h)
+
+	return r
+}
+
+// ToProjective converts an affine point to projective coordinates.
+func (c *GenericCurve[E]) ToProjective(
+	p GenericPoint[E]) ProjectivePoint[E] {
+	if p.Inf {
+		return ProjectivePoint[E]{
+			X: c.F.FromInt64(0),
+			Y: c.F.FromInt64(1),
+			Z: c.F.FromInt64(0),
+		}
+	}
+
+	return ProjectivePoint[E]{
+		X: c.F.Canonicalize(p.X),
+		Y: c.F.Canonicalize(p.Y),
+		Z: c.F.FromInt64(1),
+	}
+}
+
+// FromProjective converts a point in projective coordinates to affine
+// coordinates.
+func (c *GenericCurve[E]) FromProjective(
+	p ProjectivePoint[E]) GenericPoint[E] {
+	var zi, err = c.F.Inverse(p.Z)
+	if err != nil {
+		return GenericPoint[E]{Inf: true}
+	}
+
+	return GenericPoint[E]{
+		X: c.F.Multiply(p.X, zi),
+		Y: c.F.Multiply(p.Y, zi),
+	}
+}
+
+// DoubleProjective returns 2p.
+func (c *GenericCurve[E]) DoubleProjective(
+	p ProjectivePoint[E]) ProjectivePoint[E] {
+	var f = c.F
+
+	if c.isZero(p.Z) || c.isZero(p.Y) {
+		return c.ToProjective(GenericPoint[E]{Inf: true})
+	}
+
+	// dbl-2007-bl
+	var xx = f.Multiply(p.X, p.X)
+	var zz = f.Multiply(p.Z, p.Z)
+	var w = f.Add(f.Multiply(c.A, zz), f.Multiply(f.FromInt64(3), xx))
+	var s = f.Multiply(f.FromInt64(2), f.Multiply(p.Y, p.Z))
+	var ss = f.Multiply(s, s)
+	var sss = f.Multiply(s, ss)
+	var rr = f.Multiply(p.Y, s)
+	var rrrr = f.Multiply(rr, rr)
+	var b = f.Add(p.X, rr)
+	b = c.sub(c.sub(f.Multiply(b, b), xx), rrrr)
+	var h = c.sub(f.Multiply(w, w), f.Add(b, b))
+	var r ProjectivePoint[E]
+
+	r.X = f.Multiply(h, s)
+	r.Y = c.sub(f.Multiply(w, c.sub(b, h)), f.Add(rrrr, rrrr))
+	r.Z = sss
+
+	return r
+}
+
+// AddProjective returns p + q.
+func (c *GenericCurve[E]) AddProjective(p,
+	q ProjectivePoint[E]) ProjectivePoint[E] {
+	var f = c.F
+
+	if c.isZero(p.Z) {
+		return q
+	}
+
+	if c.isZero(q.Z) {
+		return p
+	}
+
+	// add-1998-cmo-2
+	var y1z2 = f.Multiply(p.Y, q.Z)
+	var x1z2 = f.Multiply(p.X, q.Z)
+	var z1z2 = f.Multiply(p.Z, q.Z)
+	var u = c.sub(f.Multiply(q.Y, p.Z), y1z2)
+	var v = c.sub(f.Multiply(q.X, p.Z), x1z2)
+
+	if c.isZero(v) {
+		if c.isZero(u) {
+			return c.DoubleProjective(p)
+		}
+
+		// p = -q
+		return c.ToProjective(GenericPoint[E]{Inf: true})
+	}
+
+	var uu = f.Multiply(u, u)
+	var vv = f.Multiply(v, v)
+	var vvv = f.Multiply(v, vv)
+	var rr = f.Multiply(vv, x1z2)
+	var a = c.sub(c.sub(f.Multiply(uu, z1z2), vvv), f.Add(rr, rr))
+	var r ProjectivePoint[E]
+
+	r.X = f.Multiply(v, a)
+	r.Y = c.sub(f.Multiply(u, c.sub(rr, a)), f.Multiply(vvv, y1z2))
+	r.Z = f.Multiply(vvv, z1z2)
+
+	return r
+}
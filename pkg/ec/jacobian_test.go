@@ -0,0 +1,106 @@
+package ec
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJacobianProjective(t *testing.T) {
+	var c = DemoCurve25
+	var g = c.Generic()
+	var inf = Point{Inf: true}
+
+	var jadd = func(p, q Point) Point {
+		return c.FromJacobian(g.AddJacobian(c.ToJacobian(p),
+			c.ToJacobian(q)))
+	}
+	var padd = func(p, q Point) Point {
+		return c.FromProjective(g.AddProjective(c.ToProjective(p),
+			c.ToProjective(q)))
+	}
+
+	for i := 0; i < 50; i++ {
+		var p = c.RandomPoint()
+		var q = c.RandomPoint()
+		var tests = [][2]Point{
+			{p, q},
+			{p, p},
+			{p, Point{X: p.X, Y: c.F.Neg(p.Y)}},
+			{p, inf},
+			{inf, q},
+			{inf, inf},
+		}
+
+		for _, tc := range tests {
+			var exp = c.Add(tc[0], tc[1])
+
+			assert.Equal(t, exp, jadd(tc[0], tc[1]))
+			assert.Equal(t, exp, padd(tc[0], tc[1]))
+		}
+
+		// Mix in a non-trivial Z
+		var jp = g.DoubleJacobian(c.ToJacobian(p))
+		var pp = g.DoubleProjective(c.ToProjective(p))
+		var exp = c.Add(c.Add(p, p), q)
+
+		assert.Equal(t, exp,
+			c.FromJacobian(g.AddJacobian(jp, c.ToJacobian(q))))
+		assert.Equal(t, exp,
+			c.FromProjective(g.AddProjective(pp, c.ToProjective(q))))
+	}
+}
+
+func TestScalarMAffine(t *testing.T) {
+	var c = P256
+
+	for i := int64(-5); i < 50; i++ {
+		var k = big.NewInt(i)
+
+		assert.Equal(t, c.scalarMAffine(k, c.G), c.ScalarM(k, c.G))
+	}
+
+	var g = DemoCurve25.Generic()
+	var n = DemoCurve25.N
+
+	assert.True(t, g.ScalarM(n, g.G).Inf)
+	assert.Equal(t, g.scalarMAffine(n-1, g.G), g.ScalarM(n-1, g.G))
+}
+
+// The Jacobian and affine paths are compared with double-and-add, see
+// BenchmarkScalarMBase for the fixed-base comb.
+func BenchmarkScalarM(b *testing.B) {
+	var c = *P256
+	var k = new(big.Int).Sub(c.N, big.NewInt(12345))
+
+	c.Strategy = StrategyDoubleAndAdd
+
+	b.Run("jacobian", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c.ScalarM(k, c.G)
+		}
+	})
+	b.Run("affine", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c.scalarMAffine(k, c.G)
+		}
+	})
+}
+
+func BenchmarkScalarMInt64(b *testing.B) {
+	var c = DemoCurve25.Generic()
+	var k = DemoCurve25.N - 12345
+
+	c.Strategy = StrategyDoubleAndAdd
+	b.Run("jacobian", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c.ScalarM(k, c.G)
+		}
+	})
+	b.Run("affine", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c.scalarMAffine(k, c.G)
+		}
+	})
+}
@@ -0,0 +1,326 @@
+package ec
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	"github.com/kommendorkapten/sigsim/pkg/poly"
+)
+
+// Montgomery represents an elliptic curve on Montgomery form,
+// By^2 = x^3 + Ax^2 + x, over a finite field.
+// The x coordinate of a multiple of a point can be computed from the
+// x coordinate alone, which is what X25519 is built on. Points use the
+// same Point type as Curve, with X and Y being the Montgomery u and v
+// coordinates.
+type Montgomery struct {
+	F *field.Finite
+	A int64 // A parameter
+	B int64 // B parameter
+	G Point // Generator point
+	N int64 // Order of the generator point
+	H int64 // Cofactor, the number of points divided by N
+}
+
+// DemoMontgomery is a toy curve with the same shape as Curve25519:
+// y^2 = x^3 + 486662x^2 + x, the number of points is 8 times a prime
+// and the twist has 4 times a prime points. The generator has u = 9.
+var DemoMontgomery = &Montgomery{
+	F: field.NewFinite(2147352797),
+	A: 486662,
+	B: 1,
+	G: Point{
+		X: 9,
+		Y: 384507014,
+	},
+	N: 268419413,
+	H: 8,
+}
+
+// NewMontgomery returns the Montgomery curve By^2 = x^3 + Ax^2 + x.
+// B(A^2 - 4) must not be zero, otherwise the curve is singular and an
+// error is returned.
+func NewMontgomery(f *field.Finite, a, b int64) (*Montgomery, error) {
+	var m = Montgomery{
+		F: f,
+		A: f.Canonicalize(a),
+		B: f.Canonicalize(b),
+	}
+	var d = f.Multiply(m.B, f.Add(f.Multiply(m.A, m.A), f.Neg(4)))
+
+	if d == 0 {
+		return nil, fmt.Errorf(
+			"provided parameters are not valid, a:%d b:%d",
+			a, b,
+		)
+	}
+
+	return &m, nil
+}
+
+func (m *Montgomery) String() string {
+	return fmt.Sprintf("%d %d %d %+v %d %d",
+		m.F.P(),
+		m.A,
+		m.B,
+		m.G,
+		m.N,
+		m.H,
+	)
+}
+
+// Valid returns true if the provided point is on the curve.
+func (m *Montgomery) Valid(p Point) bool {
+	if p.Inf {
+		return true
+	}
+
+	var f = m.F
+	var lhs = f.Multiply(m.B, f.Multiply(p.Y, p.Y))
+
+	return lhs == m.rhs(p.X)
+}
+
+// rhs computes x^3 + Ax^2 + x.
+func (m *Montgomery) rhs(x int64) int64 {
+	var f = m.F
+	var x2 = f.Multiply(x, x)
+
+	return f.Add(f.Add(f.Multiply(x2, x), f.Multiply(m.A, x2)), x)
+}
+
+// Add two points together and returns the resulting point, using the
+// affine addition law.
+func (m *Montgomery) Add(p, q Point) Point {
+	var f = m.F
+	var l, inv int64
+	var err error
+
+	if p.Inf {
+		return q
+	}
+
+	if q.Inf {
+		return p
+	}
+
+	if p.X == q.X {
+		if f.Add(p.Y, q.Y) == 0 {
+			// p = -q, this includes the points of order two
+			return Point{Inf: true}
+		}
+
+		// l = (3x^2 + 2Ax + 1) / 2By
+		l = f.Multiply(3, f.Multiply(p.X, p.X))
+		l = f.Add(l, f.Add(f.Multiply(f.Multiply(2, m.A), p.X), 1))
+		inv, err = f.Inverse(f.Multiply(f.Multiply(2, m.B), p.Y))
+	} else {
+		l = f.Add(q.Y, f.Neg(p.Y))
+		inv, err = f.Inverse(f.Add(q.X, f.Neg(p.X)))
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	l = f.Multiply(l, inv)
+
+	// x3 = Bl^2 - A - x1 - x2, y3 = l(x1 - x3) - y1
+	var r Point
+
+	r.X = f.Multiply(m.B, f.Multiply(l, l))
+	r.X = f.Add(r.X, f.Neg(f.Add(m.A, f.Add(p.X, q.X))))
+	r.Y = f.Add(f.Multiply(l, f.Add(p.X, f.Neg(r.X))), f.Neg(p.Y))
+
+	return r
+}
+
+// ScalarMX computes the u coordinate of kP, given only the u coordinate
+// of P, using the Montgomery ladder. k must not be negative.
+// As in X25519, the identity element is represented by zero. The
+// ladder works on both the curve and its quadratic twist, so any u
+// gives a result.
+// The number of iterations only depends on the size of the field, for
+// scalars smaller than the field.
+func (m *Montgomery) ScalarMX(k int64, u int64) int64 {
+	var f = m.F
+	// (A - 2) / 4
+	var a24, _ = f.Inverse(4)
+	a24 = f.Multiply(a24, f.Add(m.A, f.Neg(2)))
+
+	// Projective (X:Z) coordinates, the invariant is r1 - r0 = P
+	var x1 = f.Canonicalize(u)
+	var x2, z2 = int64(1), int64(0)
+	var x3, z3 = x1, int64(1)
+	var swap int64
+
+	if k < 0 {
+		panic(fmt.Sprintf("negative scalar %d", k))
+	}
+
+	var n = max(bits.Len64(uint64(f.P())), bits.Len64(uint64(k)))
+
+	for b := n - 1; b >= 0; b-- {
+		var bit = (k >> b) & 1
+
+		// Conditional swap, performed without branching as in
+		// RFC 7748
+		swap ^= bit
+		x2, x3 = cswap(swap, x2, x3)
+		z2, z3 = cswap(swap, z2, z3)
+		swap = bit
+
+		var a = f.Add(x2, z2)
+		var aa = f.Multiply(a, a)
+		var bb = f.Add(x2, f.Neg(z2))
+		var bbb = f.Multiply(bb, bb)
+		var e = f.Add(aa, f.Neg(bbb))
+		var c = f.Add(x3, z3)
+		var d = f.Add(x3, f.Neg(z3))
+		var da = f.Multiply(d, a)
+		var cb = f.Multiply(c, bb)
+
+		// Differential addition, r1 = r0 + r1
+		x3 = f.Add(da, cb)
+		x3 = f.Multiply(x3, x3)
+		z3 = f.Add(da, f.Neg(cb))
+		z3 = f.Multiply(x1, f.Multiply(z3, z3))
+		// Doubling, r0 = 2r0
+		x2 = f.Multiply(aa, bbb)
+		z2 = f.Multiply(e, f.Add(aa, f.Multiply(a24, e)))
+	}
+
+	x2, _ = cswap(swap, x2, x3)
+	z2, _ = cswap(swap, z2, z3)
+
+	// Z = 0 gives 0, as 0^(p-2) is 0
+	return f.Multiply(x2, f.Exponentiate(z2, f.P()-2))
+}
+
+// cswap returns (b, a) if swap is one and (a, b) if swap is zero.
+func cswap(swap, a, b int64) (int64, int64) {
+	var mask = -swap
+	var t = mask & (a ^ b)
+
+	return a ^ t, b ^ t
+}
+
+// Weierstrass returns the curve on short Weierstrass form which is
+// isomorphic to m, i.e y^2 = x^3 + ax + b with
+// a = (3 - A^2) / 3B^2 and b = (2A^3 - 9A) / 27B^3.
+// The generator and its order are mapped as well.
+func (m *Montgomery) Weierstrass() (*Curve, error) {
+	var f = m.F
+	var inv3, err = f.Inverse(3)
+	if err != nil {
+		return nil, errors.New("field of characteristic 3 is not supported")
+	}
+
+	var binv, _ = f.Inverse(m.B)
+	var a2 = f.Multiply(m.A, m.A)
+	var a = f.Multiply(f.Add(3, f.Neg(a2)),
+		f.Multiply(inv3, f.Multiply(binv, binv)))
+	var b = f.Add(f.Multiply(2, f.Multiply(a2, m.A)), f.Neg(f.Multiply(9, m.A)))
+	var inv27 = f.Multiply(inv3, f.Multiply(inv3, inv3))
+	b = f.Multiply(b, f.Multiply(inv27, f.Multiply(binv,
+		f.Multiply(binv, binv))))
+
+	var c *Curve
+	if c, err = NewCurve(f, a, b); err != nil {
+		return nil, err
+	}
+
+	c.G = m.ToWeierstrass(m.G)
+	c.N = m.N
+
+	return c, nil
+}
+
+// ToWeierstrass maps a point on m to the curve returned by
+// Weierstrass, (u, v) -> (u/B + A/3B, v/B).
+func (m *Montgomery) ToWeierstrass(p Point) Point {
+	var f = m.F
+
+	if p.Inf {
+		return p
+	}
+
+	var binv, _ = f.Inverse(m.B)
+	var inv3, _ = f.Inverse(3)
+
+	return Point{
+		X: f.Multiply(f.Add(p.X, f.Multiply(m.A, inv3)), binv),
+		Y: f.Multiply(p.Y, binv),
+	}
+}
+
+// FromWeierstrass maps a point on the curve returned by Weierstrass to
+// m, (x, y) -> (Bx - A/3, By).
+func (m *Montgomery) FromWeierstrass(p Point) Point {
+	var f = m.F
+
+	if p.Inf {
+		return p
+	}
+
+	var inv3, _ = f.Inverse(3)
+
+	return Point{
+		X: f.Add(f.Multiply(m.B, p.X), f.Neg(f.Multiply(m.A, inv3))),
+		Y: f.Multiply(m.B, p.Y),
+	}
+}
+
+// NewMontgomeryFromCurve returns a Montgomery curve isomorphic to c,
+// and the map from c to it. A curve y^2 = x^3 + ax + b can be written
+// on Montgomery form iff x^3 + ax + b has a root r, and 3r^2 + a is a
+// square s^2. Then A = 3r/s and B = 1/s, and (x, y) maps to
+// ((x - r)/s, y/s).
+func NewMontgomeryFromCurve(c *Curve) (*Montgomery, func(Point) Point,
+	error) {
+	var f = c.F
+	var rhs = poly.New[int64](f, c.B, c.A, 0, 1)
+
+	for _, r := range rhs.Roots() {
+		var t = f.Add(f.Multiply(3, f.Multiply(r, r)), c.A)
+		var s, err = f.Sqrt(t)
+		if err != nil {
+			continue
+		}
+
+		var sinv, _ = f.Inverse(s)
+		var m *Montgomery
+
+		m, err = NewMontgomery(f, f.Multiply(f.Multiply(3, r), sinv), sinv)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var toM = func(p Point) Point {
+			if p.Inf {
+				return p
+			}
+
+			return Point{
+				X: f.Multiply(f.Add(p.X, f.Neg(r)), sinv),
+				Y: f.Multiply(p.Y, sinv),
+			}
+		}
+
+		m.G = toM(c.G)
+		m.N = c.N
+		if m.N != 0 {
+			var n int64
+			if n, err = c.CountPoints(); err != nil {
+				return nil, nil, err
+			}
+			m.H = n / m.N
+		}
+
+		return m, toM, nil
+	}
+
+	return nil, nil, errors.New("curve has no Montgomery form")
+}
@@ -0,0 +1,114 @@
+package ec
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDemoMontgomery(t *testing.T) {
+	var m = DemoMontgomery
+	var c, err = m.Weierstrass()
+
+	assert.Nil(t, err)
+	assert.True(t, m.Valid(m.G))
+	assert.True(t, c.Valid(c.G))
+	assert.True(t, c.ScalarM(m.N, c.G).Inf)
+
+	// Cofactor 8, and cofactor 4 on the twist
+	var n int64
+	n, _, err = c.Schoof()
+	assert.Nil(t, err)
+	var twist = 2*(m.F.P()+1) - n
+
+	assert.Equal(t, m.N*m.H, n)
+	assert.Equal(t, int64(0), twist%4)
+	assert.True(t, big.NewInt(twist/4).ProbablyPrime(20))
+	assert.Equal(t, int64(0), m.ScalarMX(m.N, m.G.X))
+}
+
+func TestMontgomeryMaps(t *testing.T) {
+	var m = DemoMontgomery
+	var c, _ = m.Weierstrass()
+
+	for i := 0; i < 20; i++ {
+		var p = c.RandomPoint()
+		var q = c.RandomPoint()
+		var mp = m.FromWeierstrass(p)
+		var mq = m.FromWeierstrass(q)
+
+		assert.True(t, m.Valid(mp))
+		assert.Equal(t, p, m.ToWeierstrass(mp))
+		// The maps are group homomorphisms
+		assert.Equal(t, m.FromWeierstrass(c.Add(p, q)), m.Add(mp, mq))
+		assert.Equal(t, m.FromWeierstrass(c.Add(p, p)), m.Add(mp, mp))
+
+		for _, k := range []int64{0, 1, 2, 3, 8, 1234567, m.N - 1} {
+			var exp = m.FromWeierstrass(c.ScalarM(k, p))
+			var u = m.ScalarMX(k, mp.X)
+
+			if exp.Inf {
+				assert.Equal(t, int64(0), u)
+			} else {
+				assert.Equal(t, exp.X, u, "k %d", k)
+			}
+		}
+	}
+}
+
+func TestMontgomeryFromCurve(t *testing.T) {
+	var c, _ = DemoMontgomery.Weierstrass()
+	var m, toM, err = NewMontgomeryFromCurve(c)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(8), m.H)
+	assert.True(t, m.Valid(m.G))
+
+	for i := 0; i < 10; i++ {
+		var p = c.RandomPoint()
+		var q = c.RandomPoint()
+
+		assert.True(t, m.Valid(toM(p)))
+		assert.Equal(t, toM(c.Add(p, q)), m.Add(toM(p), toM(q)))
+	}
+
+	// Prime order curves have no point of order two
+	_, _, err = NewMontgomeryFromCurve(DemoCurve25)
+	assert.NotNil(t, err)
+
+	// y^2 = x^3 - x, all points map to the Montgomery curve
+	c = &Curve{F: field.NewFinite(71), A: 70, B: 0}
+	m, toM, err = NewMontgomeryFromCurve(c)
+	assert.Nil(t, err)
+
+	for _, p := range c.Points() {
+		assert.True(t, m.Valid(toM(p)))
+	}
+}
+
+// TestX25519 performs a Diffie-Hellman key exchange using only the u
+// coordinates, as in RFC 7748.
+func TestX25519(t *testing.T) {
+	var m = DemoMontgomery
+	var scalar = func() int64 {
+		var k, _ = rand.Int(rand.Reader, big.NewInt(m.N))
+
+		// Clamp, the scalar is a multiple of the cofactor
+		return k.Int64() * m.H
+	}
+
+	for i := 0; i < 20; i++ {
+		var a = scalar()
+		var b = scalar()
+		var pa = m.ScalarMX(a, m.G.X)
+		var pb = m.ScalarMX(b, m.G.X)
+
+		assert.Equal(t, m.ScalarMX(a, pb), m.ScalarMX(b, pa))
+	}
+
+	// A point of small order gives the all zero shared secret
+	assert.Equal(t, int64(0), m.ScalarMX(8, 0))
+}
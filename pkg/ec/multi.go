@@ -0,0 +1,120 @@
+package ec
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// MultiScalarM computes k[0]p[0] + k[1]p[1] + ... + k[n-1]p[n-1].
+// This is faster than computing each product separately, as the
+// doublings are shared between all the points. Shamir's trick is used
+// for two points and Pippenger's bucket method for more points.
+// MultiScalarM panics if k and p are of different lengths.
+func (c *GenericCurve[E]) MultiScalarM(k []E,
+	p []GenericPoint[E]) GenericPoint[E] {
+	if len(k) != len(p) {
+		panic(fmt.Sprintf("got %d scalars and %d points", len(k), len(p)))
+	}
+
+	var ks = make([]*big.Int, len(k))
+	var ps = make([]JacobianPoint[E], len(p))
+
+	// Make all scalars positive
+	for i := range k {
+		ks[i] = c.F.Int(k[i])
+		ps[i] = c.ToJacobian(p[i])
+
+		if ks[i].Sign() < 0 {
+			ks[i].Neg(ks[i])
+			ps[i] = c.negJacobian(ps[i])
+		}
+	}
+
+	switch len(k) {
+	case 0:
+		return GenericPoint[E]{Inf: true}
+	case 1:
+		return c.ScalarM(k[0], p[0])
+	case 2:
+		return c.FromJacobian(c.shamir(ks[0], ps[0], ks[1], ps[1]))
+	}
+
+	return c.FromJacobian(c.pippenger(ks, ps))
+}
+
+// shamir computes k1p1 + k2p2 with Shamir's trick, a double-and-add
+// over both scalars at once, with p1 + p2 precomputed. p1 + p2 is
+// converted to affine coordinates, so all additions are mixed.
+func (c *GenericCurve[E]) shamir(k1 *big.Int, p1 JacobianPoint[E],
+	k2 *big.Int, p2 JacobianPoint[E]) JacobianPoint[E] {
+	var r = c.ToJacobian(GenericPoint[E]{Inf: true})
+	var p12 = c.ToJacobian(c.FromJacobian(c.AddJacobian(p1, p2)))
+	var table = [4]JacobianPoint[E]{r, p1, p2, p12}
+
+	for b := max(k1.BitLen(), k2.BitLen()) - 1; b >= 0; b-- {
+		var idx = k1.Bit(b) | k2.Bit(b)<<1
+
+		r = c.double(r)
+		if idx != 0 {
+			r = c.add(r, table[idx])
+		}
+	}
+
+	return r
+}
+
+// pippenger computes the sum of k[i]p[i] with Pippenger's bucket
+// method. The scalars are split into windows of s bits. For each
+// window, every point is added to the bucket given by its digit, and
+// the buckets are then summed, weighted by their digit, with a running
+// sum.
+func (c *GenericCurve[E]) pippenger(k []*big.Int,
+	p []JacobianPoint[E]) JacobianPoint[E] {
+	var inf = c.ToJacobian(GenericPoint[E]{Inf: true})
+	var r = inf
+	var nb = 0
+	// A window of about log2(n) bits balances the number of bucket
+	// additions against the number of points.
+	var s = max(2, bits.Len(uint(len(p)))-1)
+	var buckets = make([]JacobianPoint[E], 1<<s)
+
+	for i := range k {
+		nb = max(nb, k[i].BitLen())
+	}
+
+	for w := (nb+s-1)/s - 1; w >= 0; w-- {
+		for i := 0; i < s; i++ {
+			r = c.double(r)
+		}
+
+		for i := range buckets {
+			buckets[i] = inf
+		}
+
+		for i := range k {
+			var d = 0
+
+			for j := s - 1; j >= 0; j-- {
+				d = d<<1 | int(k[i].Bit(w*s+j))
+			}
+
+			if d != 0 {
+				buckets[d] = c.add(buckets[d], p[i])
+			}
+		}
+
+		// sum(d * buckets[d]) = running sum over the buckets
+		var sum = inf
+		var acc = inf
+
+		for d := len(buckets) - 1; d > 0; d-- {
+			sum = c.add(sum, buckets[d])
+			acc = c.add(acc, sum)
+		}
+
+		r = c.add(r, acc)
+	}
+
+	return r
+}
@@ -0,0 +1,85 @@
+package ec
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiScalarM(t *testing.T) {
+	var c = DemoCurve25
+
+	for n := 0; n < 40; n++ {
+		var ks = make([]int64, n)
+		var ps = make([]Point, n)
+		var exp = Point{Inf: true}
+
+		for i := 0; i < n; i++ {
+			ks[i] = int64(i*i*7919+n) % c.N
+			if i%3 == 1 {
+				ks[i] = -ks[i]
+			}
+			ps[i] = c.RandomPoint()
+			exp = c.Add(exp, c.ScalarM(ks[i], ps[i]))
+		}
+
+		assert.Equal(t, exp, c.MultiScalarM(ks, ps), "%d points", n)
+	}
+
+	// Points summing to the identity element
+	var p = c.RandomPoint()
+	assert.True(t, c.MultiScalarM([]int64{3, -3}, []Point{p, p}).Inf)
+	assert.True(t, c.MultiScalarM([]int64{1, 1, 1, -3},
+		[]Point{p, p, p, p}).Inf)
+
+	assert.Panics(t, func() {
+		c.MultiScalarM([]int64{1}, nil)
+	})
+}
+
+func TestMultiScalarMBig(t *testing.T) {
+	var c = P256
+	var ks = make([]*big.Int, 5)
+	var ps = make([]BigPoint, 5)
+	var exp = BigPoint{Inf: true}
+
+	for i := range ks {
+		ks[i], _ = rand.Int(rand.Reader, c.N)
+		ps[i] = c.ScalarM(big.NewInt(int64(i+2)), c.G)
+		exp = c.Add(exp, c.ScalarM(ks[i], ps[i]))
+
+		assert.Equal(t, exp, c.MultiScalarM(ks[:i+1], ps[:i+1]))
+	}
+}
+
+func BenchmarkMultiScalarM(b *testing.B) {
+	var c = P256
+
+	for _, n := range []int{2, 16, 64} {
+		var ks = make([]*big.Int, n)
+		var ps = make([]BigPoint, n)
+
+		for i := range ks {
+			ks[i], _ = rand.Int(rand.Reader, c.N)
+			ps[i] = c.ScalarM(big.NewInt(int64(i+2)), c.G)
+		}
+
+		b.Run(fmt.Sprintf("separate-%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var r = BigPoint{Inf: true}
+
+				for j := range ks {
+					r = c.Add(r, c.ScalarM(ks[j], ps[j]))
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("multi-%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				c.MultiScalarM(ks, ps)
+			}
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package ec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+)
+
+// This file implements the parallel collision search of van Oorschot
+// and Wiener. All walkers use the same r-adding walk, so two walks that
+// reach the same point continue on the same path, and will reach the
+// same distinguished point. Each walker starts from a random point aG +
+// bQ, and reports the first distinguished point it finds to a central
+// collector. When the collector receives the same distinguished point
+// from two walks, the logarithm can be computed from their
+// coefficients. With m walkers the expected time is reduced by a factor
+// of m.
+// Walkers are either goroutines, or remote workers connected over a
+// socket, see RhoWorker. The messages are JSON encoded.
+
+// rhoJob is sent to remote workers. It holds everything needed to
+// perform the same walk as the collector.
+type rhoJob struct {
+	P    int64 // Order of the field
+	A, B int64 // Curve parameters
+	G, Q Point
+	N    int64 // Order of G
+	Bits int   // Trailing zero bits of distinguished points
+	R    []rhoPoint
+}
+
+// curve validates the job, and returns its curve.
+func (j *rhoJob) curve() (*Curve, error) {
+	if j.P < 3 {
+		return nil, fmt.Errorf("invalid field order %d", j.P)
+	}
+	if j.N < 2 {
+		return nil, fmt.Errorf("invalid group order %d", j.N)
+	}
+	if j.Bits < 1 || j.Bits > maxDistinguishedBits {
+		return nil, fmt.Errorf("distinguished bits %d not in [1, %d]",
+			j.Bits, maxDistinguishedBits)
+	}
+
+	var c, err = NewCurve(field.NewFinite(j.P), j.A, j.B)
+	if err != nil {
+		return nil, err
+	}
+
+	var points = []Point{j.G, j.Q}
+	for _, r := range j.R {
+		points = append(points, r.P)
+	}
+	for _, p := range points {
+		if !c.Valid(p) {
+			return nil, fmt.Errorf("point %+v is not on curve", p)
+		}
+	}
+
+	return c, nil
+}
+
+// rhoReport is sent from a walker to the collector. Steps is the number
+// of steps taken since the last report. If the walk was abandoned
+// without finding a distinguished point, Found is false.
+type rhoReport struct {
+	X     rhoPoint
+	Found bool
+	Steps int64
+}
+
+// maxDistinguishedBits is the largest number of distinguished bits
+// accepted from a collector.
+const maxDistinguishedBits = 40
+
+// walkLimit returns the number of steps after which a walk is
+// abandoned.
+func walkLimit(bits int) int64 {
+	return int64(20) << bits
+}
+
+// walkDistinguished runs walks from random starting points, and calls
+// report when each walk ends, until ctx is done or report returns an
+// error. A walk which runs for too long is likely stuck in a cycle
+// without distinguished points, and is abandoned.
+func (w *walk) walkDistinguished(ctx context.Context, bits int,
+	report func(rhoReport) error) error {
+	var limit = walkLimit(bits)
+
+	for {
+		var x, err = w.random()
+		if err != nil {
+			return err
+		}
+
+		var r rhoReport
+		for ; r.Steps < limit; r.Steps++ {
+			if r.Steps%1024 == 0 {
+				if err = ctx.Err(); err != nil {
+					return err
+				}
+			}
+
+			if distinguished(x.P, bits) {
+				r.X, r.Found = x, true
+				break
+			}
+			x = w.step(x)
+		}
+
+		if err = report(r); err != nil {
+			return err
+		}
+	}
+}
+
+// ParallelRho solves the discrete logarithm q = kg like Rho, using the
+// parallel collision search with distinguished points. cfg.Workers
+// goroutines search, and if cfg.Listener is set remote workers can join
+// the search at any time. The Cycle in cfg is ignored.
+func (c *Curve) ParallelRho(ctx context.Context, g, q Point, n int64,
+	cfg *RhoConfig) (int64, error) {
+	var conf = cfg.withDefaults(n)
+	var start = time.Now()
+
+	if conf.Listener != nil {
+		defer conf.Listener.Close()
+	}
+
+	if err := c.checkRho(g, n); err != nil {
+		return 0, err
+	}
+	if q.Inf {
+		return 0, nil
+	}
+
+	var w, err = newWalk(c, g, q, n, conf.Partitions)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	var reports = make(chan rhoReport, 64)
+
+	ctx, stop := context.WithCancel(ctx)
+	// Stop all walkers and wait for them, before returning
+	defer wg.Wait()
+	defer stop()
+
+	var send = func(r rhoReport) error {
+		select {
+		case reports <- r:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	for i := 0; i < conf.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			//nolint: errcheck
+			w.walkDistinguished(ctx, conf.DistinguishedBits, send)
+		}()
+	}
+
+	if conf.Listener != nil {
+		var job = rhoJob{
+			P:    c.F.P(),
+			A:    c.A,
+			B:    c.B,
+			G:    g,
+			Q:    q,
+			N:    n,
+			Bits: conf.DistinguishedBits,
+			R:    w.r,
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			acceptWorkers(ctx, conf.Listener, &job, w, send, &wg)
+		}()
+	}
+
+	var seen = map[Point]rhoPoint{}
+	var progress RhoProgress
+	var next = conf.ProgressInterval
+
+	for {
+		var r rhoReport
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case r = <-reports:
+		}
+
+		progress.Steps += r.Steps
+		if progress.Steps >= next && conf.Progress != nil {
+			progress.Elapsed = time.Since(start)
+			conf.Progress(progress)
+			next = (progress.Steps/conf.ProgressInterval + 1) *
+				conf.ProgressInterval
+		}
+
+		if !r.Found {
+			progress.Restarts++
+			continue
+		}
+
+		progress.Distinguished++
+		if y, ok := seen[r.X.P]; ok {
+			if y.B != r.X.B {
+				var k int64
+				if k, err = w.solve(r.X, y); err == nil {
+					return k, nil
+				}
+			}
+			// Keep the first walk, a useless collision is more
+			// likely to happen again from the new one.
+			continue
+		}
+		seen[r.X.P] = r.X
+	}
+}
+
+// check returns an error if a report received from a remote worker is
+// not consistent with the walk, i.e the number of steps is out of
+// range, or the point is not a distinguished point aG + bQ.
+func (w *walk) check(r rhoReport, bits int) error {
+	if r.Steps < 0 || r.Steps > walkLimit(bits) {
+		return fmt.Errorf("invalid number of steps %d", r.Steps)
+	}
+	if !r.Found {
+		return nil
+	}
+
+	var n = w.sf.P()
+	if r.X.A < 0 || r.X.A >= n || r.X.B < 0 || r.X.B >= n {
+		return fmt.Errorf("coefficients %d, %d are not in [0, %d)",
+			r.X.A, r.X.B, n)
+	}
+	if !distinguished(r.X.P, bits) {
+		return fmt.Errorf("%+v is not a distinguished point", r.X.P)
+	}
+
+	var p = w.c.MultiScalarM([]int64{r.X.A, r.X.B}, []Point{w.g, w.q})
+	if !p.Equal(r.X.P) {
+		return fmt.Errorf("%+v is not %dG + %dQ", r.X.P, r.X.A, r.X.B)
+	}
+
+	return nil
+}
+
+// acceptWorkers accepts remote workers on l until it's closed, and
+// passes their reports to send.
+func acceptWorkers(ctx context.Context, l net.Listener, job *rhoJob,
+	w *walk, send func(rhoReport) error, wg *sync.WaitGroup) {
+	// Unblock Accept when the search ends
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		var conn, err = l.Accept()
+		if err != nil {
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serveWorker(ctx, conn, job, w, send)
+		}()
+	}
+}
+
+// serveWorker sends the job to a remote worker, and passes its reports
+// to send until the search ends or the worker disconnects. Every report
+// is checked against the walk w, and a worker sending an invalid report
+// is disconnected.
+func serveWorker(ctx context.Context, conn net.Conn, job *rhoJob,
+	w *walk, send func(rhoReport) error) {
+	var done = make(chan struct{})
+
+	defer close(done)
+	defer conn.Close()
+
+	// Unblock Decode when the search ends
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := json.NewEncoder(conn).Encode(job); err != nil {
+		return
+	}
+
+	var dec = json.NewDecoder(conn)
+	for {
+		var r rhoReport
+
+		if err := dec.Decode(&r); err != nil {
+			return
+		}
+		if err := w.check(r, job.Bits); err != nil {
+			return
+		}
+		if err := send(r); err != nil {
+			return
+		}
+	}
+}
+
+// RhoWorker takes part in a search started by ParallelRho in another
+// process, over conn which is connected to the collector's listener.
+// The walk is received from the collector, and workers goroutines
+// report distinguished points back, until the collector closes the
+// connection. If workers is less than one, Parallel is used. The
+// connection is closed when RhoWorker returns.
+func RhoWorker(ctx context.Context, conn net.Conn, workers int) error {
+	defer conn.Close()
+
+	if workers < 1 {
+		workers = int(Parallel)
+	}
+
+	var job rhoJob
+	if err := json.NewDecoder(conn).Decode(&job); err != nil {
+		return fmt.Errorf("failed to read job: %w", err)
+	}
+
+	var c, err = job.curve()
+	if err != nil {
+		return fmt.Errorf("invalid job: %w", err)
+	}
+
+	var w = walk{
+		c:  c,
+		sf: field.NewFinite(job.N),
+		g:  job.G,
+		q:  job.Q,
+		r:  job.R,
+	}
+	if len(w.r) == 0 {
+		return errors.New("job has an empty walk")
+	}
+
+	var wctx, stop = context.WithCancel(ctx)
+	defer stop()
+
+	// Nothing more is sent by the collector, the read returns when the
+	// connection is closed
+	go func() {
+		//nolint: errcheck
+		io.Copy(io.Discard, conn)
+		stop()
+	}()
+
+	var mu sync.Mutex
+	var enc = json.NewEncoder(conn)
+	var send = func(r rhoReport) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return enc.Encode(r)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			//nolint: errcheck
+			w.walkDistinguished(wctx, job.Bits, send)
+			// The collector is gone, stop the other walkers
+			stop()
+		}()
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
@@ -0,0 +1,157 @@
+package ec
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelRho(t *testing.T) {
+	var c = DemoCurve25
+	var calls int
+	var cfg = RhoConfig{
+		Workers:          4,
+		ProgressInterval: 100,
+		Progress: func(RhoProgress) {
+			calls++
+		},
+	}
+
+	for _, k := range []int64{847079, 1, c.N - 1} {
+		var got, err = c.ParallelRho(context.Background(), c.G,
+			c.ScalarM(k, c.G), c.N, &cfg)
+
+		assert.Nil(t, err)
+		assert.Equal(t, k, got)
+	}
+	assert.NotZero(t, calls)
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	var _, err = c.ParallelRho(ctx, c.G, c.ScalarM(4711, c.G), c.N, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The order of G is not prime
+	var s = DemoSmooth
+	_, err = s.ParallelRho(context.Background(), s.G, s.G, s.N, nil)
+	assert.ErrorContains(t, err, "not prime")
+}
+
+func TestParallelRhoRemote(t *testing.T) {
+	var c = DemoCurve25
+	var tests = []struct {
+		network string
+		addr    string
+	}{
+		{network: "tcp", addr: "127.0.0.1:0"},
+		{network: "unix", addr: filepath.Join(t.TempDir(), "rho.sock")},
+	}
+
+	for _, tc := range tests {
+		var l, err = net.Listen(tc.network, tc.addr)
+		assert.Nil(t, err, tc.network)
+
+		// Only remote workers search
+		var cfg = RhoConfig{Workers: -1, Listener: l}
+		var errs = make(chan error, 2)
+
+		for i := 0; i < 2; i++ {
+			go func() {
+				var conn, err = net.Dial(tc.network, l.Addr().String())
+				if err != nil {
+					errs <- err
+					return
+				}
+				errs <- RhoWorker(context.Background(), conn, 2)
+			}()
+		}
+
+		var k int64
+		k, err = c.ParallelRho(context.Background(), c.G,
+			c.ScalarM(847079, c.G), c.N, &cfg)
+		assert.Nil(t, err, tc.network)
+		assert.Equal(t, int64(847079), k, tc.network)
+
+		// The workers stop when the collector is done
+		assert.Nil(t, <-errs, tc.network)
+		assert.Nil(t, <-errs, tc.network)
+	}
+}
+
+func TestRhoJobCurve(t *testing.T) {
+	var c = DemoCurve25
+	var valid = rhoJob{
+		P:    c.F.P(),
+		A:    c.A,
+		B:    c.B,
+		G:    c.G,
+		Q:    c.ScalarM(4711, c.G),
+		N:    c.N,
+		Bits: 6,
+		R:    []rhoPoint{{P: c.G, A: 1}},
+	}
+	var _, err = valid.curve()
+	assert.Nil(t, err)
+
+	var tests = []struct {
+		name string
+		mod  func(j *rhoJob)
+	}{
+		{name: "field", mod: func(j *rhoJob) { j.P = 2 }},
+		{name: "group", mod: func(j *rhoJob) { j.N = 0 }},
+		{name: "no bits", mod: func(j *rhoJob) { j.Bits = 0 }},
+		{name: "many bits", mod: func(j *rhoJob) { j.Bits = 63 }},
+		{name: "g", mod: func(j *rhoJob) { j.G.X++ }},
+		{name: "q", mod: func(j *rhoJob) { j.Q.Y++ }},
+		{name: "r", mod: func(j *rhoJob) {
+			j.R = []rhoPoint{{P: Point{X: 1, Y: 1}}}
+		}},
+	}
+
+	for _, tc := range tests {
+		var j = valid
+
+		tc.mod(&j)
+		_, err = j.curve()
+		assert.NotNil(t, err, tc.name)
+	}
+}
+
+func TestWalkCheck(t *testing.T) {
+	var c = DemoCurve25
+	var q = c.ScalarM(4711, c.G)
+	var w, err = newWalk(c, c.G, q, c.N, DefaultPartitions)
+	assert.Nil(t, err)
+
+	// Find a distinguished point
+	var x rhoPoint
+	x, err = w.random()
+	assert.Nil(t, err)
+	for !distinguished(x.P, 2) {
+		x = w.step(x)
+	}
+	assert.Nil(t, w.check(rhoReport{X: x, Found: true, Steps: 10}, 2))
+	assert.Nil(t, w.check(rhoReport{Steps: walkLimit(2)}, 2))
+
+	var tests = []struct {
+		name string
+		r    rhoReport
+	}{
+		{name: "negative steps", r: rhoReport{Steps: -1}},
+		{name: "many steps", r: rhoReport{Steps: walkLimit(2) + 1}},
+		{name: "a", r: rhoReport{X: rhoPoint{P: x.P, A: x.A + c.N,
+			B: x.B}, Found: true}},
+		{name: "b", r: rhoReport{X: rhoPoint{P: x.P, A: x.A,
+			B: -1}, Found: true}},
+		{name: "point", r: rhoReport{X: rhoPoint{P: x.P,
+			A: (x.A + 1) % c.N, B: x.B}, Found: true}},
+	}
+
+	for _, tc := range tests {
+		assert.NotNil(t, w.check(tc.r, 2), tc.name)
+	}
+}
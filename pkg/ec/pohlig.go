@@ -0,0 +1,84 @@
+package ec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	smath "github.com/kommendorkapten/sigsim/pkg/math"
+)
+
+// PohligHellman solves the discrete logarithm q = kg, where g has order
+// n, with the Pohlig-Hellman algorithm. The problem is split into one
+// problem per prime power p^e dividing n: k mod p^e is computed one
+// base p digit at a time, each being a logarithm in the subgroup of
+// order p, solved with SolveDLP. k is then combined with the Chinese
+// remainder theorem.
+// The running time is dominated by the largest prime factor of n, so if
+// n is smooth the logarithm is easy, no matter how large n is. This is
+// why the order of the generator must be a large prime.
+// If q is not a multiple of g, ErrNoLog is returned.
+func (c *Curve) PohligHellman(ctx context.Context, g, q Point,
+	n int64) (int64, error) {
+	if g.Inf {
+		return 0, errors.New("base point is the identity element")
+	}
+	if n <= 0 || !c.ScalarM(n, g).Inf {
+		return 0, fmt.Errorf("base point is not of order %d", n)
+	}
+
+	var k, m = big.NewInt(0), big.NewInt(1)
+	var pfs = smath.PrimeFactors(n)
+
+	for i := 0; i < len(pfs); {
+		var p = pfs[i]
+		var e int
+
+		for i < len(pfs) && pfs[i] == p {
+			e++
+			i++
+		}
+
+		var x, err = c.pohligHellmanPrime(ctx, g, q, n, p, e)
+		if err != nil {
+			return 0, err
+		}
+
+		var pe = new(big.Int).Exp(big.NewInt(p), big.NewInt(int64(e)),
+			nil)
+		k = crt(k, m, big.NewInt(x), pe)
+		m.Mul(m, pe)
+	}
+
+	if !c.ScalarM(k.Int64(), g).Equal(q) {
+		return 0, ErrNoLog
+	}
+
+	return k.Int64(), nil
+}
+
+// pohligHellmanPrime returns k mod p^e. With x the digits found so far,
+// (n / p^(i+1))(q - xg) = d_i(n / p)g, where d_i is the next digit.
+func (c *Curve) pohligHellmanPrime(ctx context.Context, g, q Point, n,
+	p int64, e int) (int64, error) {
+	var g0 = c.ScalarM(n/p, g)
+	var x, pi = int64(0), int64(1)
+
+	for i := 0; i < e; i++ {
+		// q - xg, -xg = (n - x)g
+		var h = c.Add(q, c.ScalarM((n-x)%n, g))
+		h = c.ScalarM(n/(pi*p), h)
+
+		var d, err = c.SolveDLP(ctx, g0, h, p)
+		if err != nil {
+			return 0, fmt.Errorf("failed to solve for %d^%d: %w", p, i+1,
+				err)
+		}
+
+		x += d * pi
+		pi *= p
+	}
+
+	return x, nil
+}
@@ -0,0 +1,58 @@
+package ec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDemoSmooth(t *testing.T) {
+	var c = DemoSmooth
+	var n, err = c.CountPoints()
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2147420040), n)
+	assert.Equal(t, 2*c.N, n)
+	assert.True(t, c.Valid(c.G))
+	assert.True(t, c.ScalarM(c.N, c.G).Inf)
+	assert.False(t, c.ScalarM(c.N/2, c.G).Inf)
+
+	// Verify rejects the curve as N is not prime
+	var failed []string
+	for _, f := range c.VerifyReport().Failed() {
+		failed = append(failed, f.Name)
+	}
+	assert.Contains(t, failed, "order")
+}
+
+func TestPohligHellman(t *testing.T) {
+	var tests = []struct {
+		name string
+		c    *Curve
+		n    int64
+		k    int64
+	}{
+		{name: "smooth", c: DemoSmooth, n: DemoSmooth.N, k: 847079},
+		{name: "smooth", c: DemoSmooth, n: DemoSmooth.N, k: 0},
+		{name: "smooth", c: DemoSmooth, n: DemoSmooth.N,
+			k: DemoSmooth.N - 1},
+		{name: "smooth", c: DemoSmooth, n: DemoSmooth.N, k: 1 << 29},
+		// A prime order is a single subproblem
+		{name: "prime", c: DemoCurve25, n: DemoCurve25.N, k: 31337},
+	}
+
+	for _, tc := range tests {
+		var c = tc.c
+		var k, err = c.PohligHellman(context.Background(), c.G,
+			c.ScalarM(tc.k, c.G), tc.n)
+
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tc.k, k, tc.name)
+	}
+
+	// Wrong order
+	var _, err = DemoSmooth.PohligHellman(context.Background(),
+		DemoSmooth.G, DemoSmooth.G, DemoSmooth.N/2)
+	assert.NotNil(t, err)
+}
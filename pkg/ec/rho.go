@@ -0,0 +1,388 @@
+package ec
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+)
+
+// Cycle selects how Rho detects that the walk has collided.
+type Cycle int
+
+const (
+	// CycleFloyd runs a second walk at double speed until it meets the
+	// first one. Three group operations are needed per step.
+	CycleFloyd Cycle = iota
+	// CycleBrent compares the walk to a point saved at every power of
+	// two. One group operation is needed per step.
+	CycleBrent
+	// CycleDistinguished stores the distinguished points, the points
+	// whose x coordinate has a number of trailing zero bits, and looks
+	// for a point being reached twice. This is what makes the search
+	// parallelizable.
+	CycleDistinguished
+)
+
+func (c Cycle) String() string {
+	switch c {
+	case CycleFloyd:
+		return "floyd"
+	case CycleBrent:
+		return "brent"
+	case CycleDistinguished:
+		return "distinguished"
+	}
+
+	return fmt.Sprintf("Cycle(%d)", int(c))
+}
+
+// DefaultPartitions is the number of partitions used in the r-adding
+// walk, if not set in RhoConfig. With 20 partitions the walk behaves
+// close to a random walk.
+const DefaultPartitions = 20
+
+// DefaultProgressInterval is how many steps there are between progress
+// reports, if not set in RhoConfig.
+const DefaultProgressInterval = 1 << 20
+
+// ErrNoLog is returned when q is not a multiple of g.
+var ErrNoLog = errors.New("no discrete logarithm found")
+
+// RhoProgress is reported periodically while Rho runs.
+type RhoProgress struct {
+	Steps         int64 // Number of steps taken
+	Restarts      int64 // Number of walks restarted
+	Distinguished int64 // Number of distinguished points found
+	Elapsed       time.Duration
+}
+
+// RhoConfig configures Rho. The zero value uses Floyd's cycle
+// detection and the default settings.
+type RhoConfig struct {
+	Cycle Cycle
+	// Partitions is the number of precomputed points in the r-adding
+	// walk. If zero, DefaultPartitions is used.
+	Partitions int
+	// DistinguishedBits is the number of trailing zero bits in the x
+	// coordinate of a distinguished point. If zero, a quarter of the
+	// bit length of the order is used.
+	DistinguishedBits int
+	// Progress, if set, is called every ProgressInterval steps.
+	Progress         func(RhoProgress)
+	ProgressInterval int64
+	// Workers is the number of goroutines used by ParallelRho. If zero,
+	// Parallel is used, and if negative only remote workers search.
+	Workers int
+	// Listener, if set, accepts remote workers for ParallelRho. See
+	// RhoWorker. The listener is closed when the search ends.
+	Listener net.Listener
+}
+
+// withDefaults returns a copy of cfg, with the defaults used for unset
+// fields. n is the order of the group.
+func (cfg *RhoConfig) withDefaults(n int64) RhoConfig {
+	var r RhoConfig
+
+	if cfg != nil {
+		r = *cfg
+	}
+	if r.Partitions <= 0 {
+		r.Partitions = DefaultPartitions
+	}
+	if r.DistinguishedBits <= 0 {
+		r.DistinguishedBits = max(1, big.NewInt(n).BitLen()/4)
+	}
+	if r.ProgressInterval <= 0 {
+		r.ProgressInterval = DefaultProgressInterval
+	}
+	if r.Workers == 0 {
+		r.Workers = int(Parallel)
+	}
+
+	return r
+}
+
+// checkRho verifies that g is of prime order n.
+func (c *Curve) checkRho(g Point, n int64) error {
+	if g.Inf {
+		return errors.New("base point is the identity element")
+	}
+	// The logarithm is solved mod n, which needs inverses mod n
+	if !big.NewInt(n).ProbablyPrime(32) {
+		return fmt.Errorf("order %d is not prime", n)
+	}
+	if !c.ScalarM(n, g).Inf {
+		return fmt.Errorf("base point is not of order %d", n)
+	}
+
+	return nil
+}
+
+// rhoPoint is a point of the walk, P = aG + bQ.
+type rhoPoint struct {
+	P    Point
+	A, B int64
+}
+
+// walk is an r-adding walk, the next point is P + R[i] where i is
+// given by the x coordinate of P, and R[i] = a_i G + b_i Q.
+type walk struct {
+	c  *Curve
+	sf *field.Finite // Integers mod the order n
+	g  Point
+	q  Point
+	r  []rhoPoint
+}
+
+func newWalk(c *Curve, g, q Point, n int64, partitions int) (*walk,
+	error) {
+	var w = walk{
+		c:  c,
+		sf: field.NewFinite(n),
+		g:  g,
+		q:  q,
+		r:  make([]rhoPoint, partitions),
+	}
+
+	for i := range w.r {
+		var err error
+
+		if w.r[i], err = w.random(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &w, nil
+}
+
+// random returns a random point aG + bQ.
+func (w *walk) random() (rhoPoint, error) {
+	var n = big.NewInt(w.sf.P())
+	var a, b *big.Int
+	var err error
+
+	if a, err = rand.Int(rand.Reader, n); err != nil {
+		return rhoPoint{}, err
+	}
+	if b, err = rand.Int(rand.Reader, n); err != nil {
+		return rhoPoint{}, err
+	}
+
+	return rhoPoint{
+		P: w.c.MultiScalarM([]int64{a.Int64(), b.Int64()},
+			[]Point{w.g, w.q}),
+		A: a.Int64(),
+		B: b.Int64(),
+	}, nil
+}
+
+// step returns the next point of the walk.
+func (w *walk) step(x rhoPoint) rhoPoint {
+	var i int
+
+	if !x.P.Inf {
+		i = int(x.P.X % int64(len(w.r)))
+	}
+
+	var r = w.r[i]
+
+	return rhoPoint{
+		P: w.c.Add(x.P, r.P),
+		A: w.sf.Add(x.A, r.A),
+		B: w.sf.Add(x.B, r.B),
+	}
+}
+
+// solve returns k from a collision aG + bQ = a'G + b'Q, as
+// k = (a - a') / (b' - b). If b = b' the collision is useless, and an
+// error is returned.
+func (w *walk) solve(x, y rhoPoint) (int64, error) {
+	var inv, err = w.sf.Inverse(w.sf.Add(y.B, w.sf.Neg(x.B)))
+	if err != nil {
+		return 0, err
+	}
+
+	var k = w.sf.Multiply(w.sf.Add(x.A, w.sf.Neg(y.A)), inv)
+
+	if !w.c.ScalarM(k, w.g).Equal(w.q) {
+		return 0, ErrNoLog
+	}
+
+	return k, nil
+}
+
+// distinguished returns true if the x coordinate of p has at least
+// bits trailing zero bits.
+func distinguished(p Point, bits int) bool {
+	return !p.Inf && p.X&(int64(1)<<bits-1) == 0
+}
+
+// rhoRun holds the state of a Rho invocation.
+type rhoRun struct {
+	ctx      context.Context
+	cfg      RhoConfig
+	progress RhoProgress
+	start    time.Time
+}
+
+// tick counts a step, reports progress and checks for cancellation.
+func (r *rhoRun) tick() error {
+	r.progress.Steps++
+
+	if r.progress.Steps%r.cfg.ProgressInterval == 0 &&
+		r.cfg.Progress != nil {
+		r.progress.Elapsed = time.Since(r.start)
+		r.cfg.Progress(r.progress)
+	}
+
+	if r.progress.Steps%1024 == 0 {
+		return r.ctx.Err()
+	}
+
+	return nil
+}
+
+// Rho solves the discrete logarithm q = kg with Pollard's rho, where g
+// has prime order n, e.g G and N of the curve. The expected number of
+// steps is sqrt(pi * n / 2). A walk which ends in a useless collision is
+// restarted from a new random point. An error is returned if n is not
+// prime. If q is not a multiple of g the search will not terminate, use
+// a context with a deadline.
+// cfg may be nil, in which case the defaults are used.
+func (c *Curve) Rho(ctx context.Context, g, q Point, n int64,
+	cfg *RhoConfig) (int64, error) {
+	var r = rhoRun{
+		ctx:   ctx,
+		cfg:   cfg.withDefaults(n),
+		start: time.Now(),
+	}
+
+	if err := c.checkRho(g, n); err != nil {
+		return 0, err
+	}
+	if q.Inf {
+		return 0, nil
+	}
+
+	var w, err = newWalk(c, g, q, n, r.cfg.Partitions)
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		var k int64
+
+		switch r.cfg.Cycle {
+		case CycleFloyd:
+			k, err = r.floyd(w)
+		case CycleBrent:
+			k, err = r.brent(w)
+		case CycleDistinguished:
+			k, err = r.distinguished(w)
+		default:
+			return 0, fmt.Errorf("unknown cycle detection %s", r.cfg.Cycle)
+		}
+
+		if err == nil {
+			return k, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return 0, ctxErr
+		}
+
+		r.progress.Restarts++
+	}
+}
+
+// floyd walks until x_i = x_2i.
+func (r *rhoRun) floyd(w *walk) (int64, error) {
+	var x, err = w.random()
+	if err != nil {
+		return 0, err
+	}
+	var y = x
+
+	for {
+		x = w.step(x)
+		y = w.step(w.step(y))
+
+		if err = r.tick(); err != nil {
+			return 0, err
+		}
+
+		if x.P.Equal(y.P) {
+			return w.solve(x, y)
+		}
+	}
+}
+
+// brent walks until x_i = x_j, where j is the largest power of two
+// smaller than i.
+func (r *rhoRun) brent(w *walk) (int64, error) {
+	var x, err = w.random()
+	if err != nil {
+		return 0, err
+	}
+	var saved = x
+	var power, lam = int64(1), int64(0)
+
+	for {
+		x = w.step(x)
+		lam++
+
+		if err = r.tick(); err != nil {
+			return 0, err
+		}
+
+		if x.P.Equal(saved.P) {
+			return w.solve(x, saved)
+		}
+
+		if lam == power {
+			saved = x
+			power *= 2
+			lam = 0
+		}
+	}
+}
+
+// distinguished walks from random points until a distinguished point is
+// reached twice. A walk which runs for too long is likely stuck in a
+// cycle without distinguished points, and is abandoned.
+func (r *rhoRun) distinguished(w *walk) (int64, error) {
+	var seen = map[Point]rhoPoint{}
+	var bits = r.cfg.DistinguishedBits
+	var limit = int64(20) << bits
+
+	for {
+		var x, err = w.random()
+		if err != nil {
+			return 0, err
+		}
+
+		for i := int64(0); i < limit; i++ {
+			if err = r.tick(); err != nil {
+				return 0, err
+			}
+
+			if !distinguished(x.P, bits) {
+				x = w.step(x)
+				continue
+			}
+
+			r.progress.Distinguished++
+			if y, ok := seen[x.P]; ok && y.B != x.B {
+				return w.solve(x, y)
+			}
+			seen[x.P] = x
+
+			break
+		}
+	}
+}
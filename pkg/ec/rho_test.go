@@ -0,0 +1,83 @@
+package ec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRho(t *testing.T) {
+	var c = DemoCurve25
+	var tests = []struct {
+		cycle Cycle
+		k     int64
+	}{
+		{cycle: CycleFloyd, k: 847079},
+		{cycle: CycleBrent, k: 847079},
+		{cycle: CycleDistinguished, k: 847079},
+		{cycle: CycleFloyd, k: c.N - 1},
+		{cycle: CycleBrent, k: 1},
+		{cycle: CycleDistinguished, k: 31337},
+	}
+
+	for _, tc := range tests {
+		var q = c.ScalarM(tc.k, c.G)
+		var k, err = c.Rho(context.Background(), c.G, q, c.N,
+			&RhoConfig{Cycle: tc.cycle})
+
+		assert.Nil(t, err, tc.cycle.String())
+		assert.Equal(t, tc.k, k, tc.cycle.String())
+	}
+
+	// The identity element
+	var k, err = c.Rho(context.Background(), c.G, Point{Inf: true}, c.N,
+		nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), k)
+
+	// Wrong order
+	_, err = c.Rho(context.Background(), c.G, c.G, c.N-1, nil)
+	assert.NotNil(t, err)
+
+	// The order of G is not prime
+	var s = DemoSmooth
+	_, err = s.Rho(context.Background(), s.G, s.G, s.N, nil)
+	assert.ErrorContains(t, err, "not prime")
+}
+
+func TestRhoProgress(t *testing.T) {
+	var c = DemoCurve25
+	var reports []RhoProgress
+	var cfg = RhoConfig{
+		Cycle:            CycleBrent,
+		ProgressInterval: 100,
+		Progress: func(p RhoProgress) {
+			reports = append(reports, p)
+		},
+	}
+
+	var _, err = c.Rho(context.Background(), c.G, c.ScalarM(4711, c.G),
+		c.N, &cfg)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, reports)
+
+	for i, p := range reports {
+		assert.Equal(t, int64(100*(i+1)), p.Steps)
+	}
+}
+
+func TestRhoCancel(t *testing.T) {
+	var c = DemoCurve25
+	var ctx, cancel = context.WithCancel(context.Background())
+
+	cancel()
+
+	for _, cycle := range []Cycle{CycleFloyd, CycleBrent,
+		CycleDistinguished} {
+		var _, err = c.Rho(ctx, c.G, c.ScalarM(4711, c.G), c.N,
+			&RhoConfig{Cycle: cycle})
+
+		assert.ErrorIs(t, err, context.Canceled, cycle.String())
+	}
+}
@@ -0,0 +1,177 @@
+package ec
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Strategy selects the algorithm used for scalar multiplication.
+type Strategy int
+
+const (
+	// StrategyAuto lets the curve pick the algorithm. Multiples of G
+	// are computed with a cached fixed-base comb table, and wNAF is
+	// used for other points.
+	StrategyAuto Strategy = iota
+	// StrategyDoubleAndAdd is the left to right double-and-add. An
+	// addition is only performed for the set bits of the scalar, so
+	// the sequence of operations reveals the scalar.
+	StrategyDoubleAndAdd
+	// StrategyLadder is the Montgomery ladder. Every bit results in
+	// exactly one addition and one doubling, and the number of
+	// iterations does not depend on the scalar.
+	StrategyLadder
+	// StrategyWNAF uses the windowed non-adjacent form of the scalar,
+	// with the window size selected by the curve's Window.
+	StrategyWNAF
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case StrategyAuto:
+		return "auto"
+	case StrategyDoubleAndAdd:
+		return "double-and-add"
+	case StrategyLadder:
+		return "ladder"
+	case StrategyWNAF:
+		return "wnaf"
+	}
+
+	return fmt.Sprintf("Strategy(%d)", int(s))
+}
+
+// Op is a group operation performed during scalar multiplication.
+type Op int
+
+const (
+	// OpAdd is the addition of two points.
+	OpAdd Op = iota
+	// OpDouble is the doubling of a point.
+	OpDouble
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpAdd:
+		return "A"
+	case OpDouble:
+		return "D"
+	}
+
+	return fmt.Sprintf("Op(%d)", int(o))
+}
+
+// trace reports op to the trace hook, if any.
+func (c *GenericCurve[E]) trace(op Op) {
+	if c.Trace != nil {
+		c.Trace(op)
+	}
+}
+
+func (c *GenericCurve[E]) add(p, q JacobianPoint[E]) JacobianPoint[E] {
+	c.trace(OpAdd)
+
+	return c.AddJacobian(p, q)
+}
+
+func (c *GenericCurve[E]) double(p JacobianPoint[E]) JacobianPoint[E] {
+	c.trace(OpDouble)
+
+	return c.DoubleJacobian(p)
+}
+
+// ScalarM calculates the scalar multiplication of a point.
+// This function is the meat of ec cryptography. Solving the inverse:
+// xP = P' for a known P and P' is the discrete logarithm problem for
+// elliptic curves.
+// The algorithm is selected by the curve's Strategy. The computations
+// are performed in Jacobian coordinates, so only a single inversion is
+// needed.
+func (c *GenericCurve[E]) ScalarM(k E, p GenericPoint[E]) GenericPoint[E] {
+	var kb = c.F.Int(k)
+	var neg = kb.Sign() < 0
+	var r JacobianPoint[E]
+
+	if neg {
+		kb.Neg(kb)
+	}
+
+	switch c.Strategy {
+	case StrategyDoubleAndAdd:
+		r = c.doubleAndAdd(kb, c.ToJacobian(p))
+	case StrategyLadder:
+		r = c.ladder(kb, c.ToJacobian(p))
+	case StrategyWNAF:
+		r = c.wnafM(kb, c.ToJacobian(p))
+	default:
+		var ok bool
+
+		if c.hasG() && !p.Inf && c.Equal(p, c.G) {
+			r, ok = c.combM(kb)
+		}
+
+		if !ok {
+			r = c.wnafM(kb, c.ToJacobian(p))
+		}
+	}
+
+	if neg {
+		r = c.negJacobian(r)
+	}
+
+	return c.FromJacobian(r)
+}
+
+// doubleAndAdd computes kp with the left to right double-and-add
+// algorithm. p is kept in affine coordinates (Z = 1), which allows for
+// a faster addition.
+func (c *GenericCurve[E]) doubleAndAdd(k *big.Int,
+	p JacobianPoint[E]) JacobianPoint[E] {
+	if k.Sign() == 0 {
+		return c.ToJacobian(GenericPoint[E]{Inf: true})
+	}
+
+	// The most significant bit is always set
+	var r = p
+
+	for b := k.BitLen() - 2; b >= 0; b-- {
+		r = c.double(r)
+		if k.Bit(b) != 0 {
+			r = c.add(r, p)
+		}
+	}
+
+	return r
+}
+
+// ladderBits returns the number of iterations used by the ladder. It's
+// fixed for the curve so the running time does not reveal the length
+// of the scalar. Any point's order is at most p + 1 + 2*sqrt(p) < 2p,
+// so reduced scalars fit in the bit length of p plus one.
+func (c *GenericCurve[E]) ladderBits(k *big.Int) int {
+	var n = c.F.Modulus().BitLen() + 1
+
+	return max(n, k.BitLen())
+}
+
+// ladder computes kp with the Montgomery ladder. The invariant is that
+// r1 - r0 = p, and for each bit one of them is doubled and the sum is
+// stored in the other.
+func (c *GenericCurve[E]) ladder(k *big.Int,
+	p JacobianPoint[E]) JacobianPoint[E] {
+	var r0 = c.ToJacobian(GenericPoint[E]{Inf: true})
+	var r1 = p
+
+	for b := c.ladderBits(k) - 1; b >= 0; b-- {
+		if k.Bit(b) == 0 {
+			r1 = c.add(r0, r1)
+			r0 = c.double(r0)
+		} else {
+			r0 = c.add(r0, r1)
+			r1 = c.double(r1)
+		}
+	}
+
+	return r0
+}
@@ -0,0 +1,180 @@
+package ec
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategies(t *testing.T) {
+	var c = *DemoCurve25
+	var ks = []int64{0, 1, 2, 3, 11, 1000, c.N - 1, c.N, c.N + 1, -5}
+
+	for i := 0; i < 10; i++ {
+		var p = c.RandomPoint()
+
+		for _, k := range ks {
+			c.Strategy = StrategyDoubleAndAdd
+			var exp = c.ScalarM(k, p)
+
+			c.Strategy = StrategyAuto
+			assert.Equal(t, exp, c.ScalarM(k, p), "auto %d", k)
+			assert.Equal(t, c.ScalarM(k, c.G),
+				Point(c.Generic().scalarMAffine(k, c.Generic().G)),
+				"fixed-base %d", k)
+			c.Strategy = StrategyLadder
+			assert.Equal(t, exp, c.ScalarM(k, p), "ladder %d", k)
+			c.Strategy = StrategyWNAF
+			assert.Equal(t, exp, c.ScalarM(k, p), "wnaf %d", k)
+		}
+	}
+
+	// Big curves
+	var bc = *P256
+	var k = new(big.Int).Sub(bc.N, big.NewInt(77))
+	var exp = bc.ScalarM(k, bc.G)
+
+	bc.Strategy = StrategyLadder
+	assert.Equal(t, exp, bc.ScalarM(k, bc.G))
+}
+
+func TestTrace(t *testing.T) {
+	var c = *DemoCurve25
+	var ops strings.Builder
+	var trace = func(k int64) string {
+		ops.Reset()
+		c.ScalarM(k, c.G)
+
+		return ops.String()
+	}
+
+	c.Trace = func(op Op) {
+		ops.WriteString(op.String())
+	}
+
+	// The additions reveal the bits of the scalar
+	c.Strategy = StrategyDoubleAndAdd
+	assert.Equal(t, "DDADA", trace(0b1011))
+	assert.Equal(t, "DDD", trace(0b1000))
+	assert.Equal(t, "", trace(1))
+
+	// The ladder performs the same operations for any scalar, 26
+	// iterations as the field is 25 bits.
+	c.Strategy = StrategyLadder
+	var exp = strings.Repeat("AD", 26)
+	assert.Equal(t, exp, trace(0b1011))
+	assert.Equal(t, exp, trace(0b1000))
+	assert.Equal(t, exp, trace(c.N-1))
+}
+
+func TestWNAF(t *testing.T) {
+	for w := 2; w < 8; w++ {
+		for i := int64(0); i < 2000; i++ {
+			var k = big.NewInt(i * 7919)
+			var digits = wnaf(k, w)
+			var sum = new(big.Int)
+			var last = len(digits) + w
+
+			for j := len(digits) - 1; j >= 0; j-- {
+				var d = digits[j]
+
+				sum.Lsh(sum, 1)
+				sum.Add(sum, big.NewInt(int64(d)))
+
+				if d != 0 {
+					assert.Equal(t, 1, d&1, "digit %d is even", d)
+					assert.Less(t, d, 1<<(w-1))
+					assert.Greater(t, d, -(1 << (w - 1)))
+					assert.GreaterOrEqual(t, last-j, w,
+						"adjacent digits for %d", k)
+					last = j
+				}
+			}
+
+			assert.Equal(t, k, sum)
+		}
+	}
+}
+
+func TestFixedBase(t *testing.T) {
+	var c = *DemoCurve25
+
+	// Start from an empty cache, so the test can be repeated
+	fixedBases.Lock()
+	fixedBases.m = map[string]any{}
+	fixedBases.Unlock()
+	var g = c.Generic()
+
+	for _, w := range []int{0, 2, 3, 5, 8} {
+		c.Window = w
+
+		for i := 0; i < 20; i++ {
+			var k = int64(i*i*1000003) % c.N
+
+			assert.Equal(t, Point(g.scalarMAffine(k, g.G)),
+				c.ScalarM(k, c.G), "window %d k %d", w, k)
+		}
+
+		var exp = w
+		if w == 0 {
+			exp = DefaultWindow
+		}
+		assert.Equal(t, exp, c.Generic().cachedFixedBase().w)
+	}
+
+	// Scalars larger than the table
+	var k = int64(1) << 40
+	assert.Equal(t, Point(g.scalarMAffine(k, g.G)), c.ScalarM(k, c.G))
+
+	// The table is rebuilt for a new generator
+	c.G = c.ScalarM(2, c.G)
+	assert.Equal(t, Point(g.scalarMAffine(10, g.G)), c.ScalarM(5, c.G))
+
+	// and for new curve parameters
+	c.B++
+	assert.Nil(t, c.Generic().cachedFixedBase())
+	c.ScalarM(5, c.G)
+	assert.NotNil(t, c.Generic().cachedFixedBase())
+
+	// Other points don't create a table
+	c.B++
+	c.ScalarM(5, c.RandomPoint())
+	assert.Nil(t, c.Generic().cachedFixedBase())
+
+	// Copies share the table
+	var cc = *DemoCurve25
+	cc.ScalarM(5, cc.G)
+	var fb = cc.Generic().cachedFixedBase()
+	var cp = cc
+	assert.Same(t, fb, cp.Generic().cachedFixedBase())
+}
+
+func BenchmarkScalarMBase(b *testing.B) {
+	var k = new(big.Int).Sub(P256.N, big.NewInt(12345))
+
+	for _, s := range []Strategy{StrategyDoubleAndAdd, StrategyWNAF} {
+		var c = *P256
+
+		c.Strategy = s
+		b.Run(s.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				c.ScalarM(k, c.G)
+			}
+		})
+	}
+
+	for _, w := range []int{2, 4, 6, 8} {
+		var c = *P256
+
+		c.Window = w
+		c.ScalarM(k, c.G)
+		b.Run(fmt.Sprintf("comb-%d", w), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				c.ScalarM(k, c.G)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package ec
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kommendorkapten/sigsim/pkg/poly"
+)
+
+// This file implements Schoof's algorithm for counting the points on
+// a curve. The number of points is p + 1 - t, where t is the trace of
+// Frobenius. By Hasse's theorem |t| <= 2*sqrt(p), so it's sufficient to
+// know t mod l for enough small primes l so that their product exceeds
+// 4*sqrt(p), and then use the Chinese remainder theorem.
+// To find t mod l, the Frobenius endomorphism pi(x, y) = (x^p, y^p)
+// is used. It satisfies pi^2 - t*pi + p = 0, and so for any l-torsion
+// point P, pi^2(P) + (p mod l)P = (t mod l)pi(P). The arithmetic is
+// performed on "symbolic" l-torsion points, i.e with coordinates in
+// F_p[x, y] / (psi_l(x), y^2 - x^3 - ax - b), where psi_l is the l:th
+// division polynomial whose roots are the x coordinates of the l-torsion
+// points.
+// See https://math.mit.edu/classes/18.783/2015/LectureNotes9.pdf for
+// more details.
+
+// endo is an element of E(F_p[x, y] / (h(x), y^2 - f(x))), i.e a point
+// (a(x), b(x)y). It's used to represent endomorphisms restricted to the
+// points whose x coordinates are roots of h.
+type endo[E any] struct {
+	a, b poly.Poly[E]
+	zero bool
+}
+
+// endoRing performs the group law on endo points, modulo h.
+type endoRing[E any] struct {
+	c *GenericCurve[E]
+	h poly.Poly[E]
+	f poly.Poly[E] // x^3 + ax + b mod h
+}
+
+// factorError is returned when a non-invertible element was found
+// during the computations. It holds a non-trivial factor of h.
+type factorError[E any] struct {
+	g poly.Poly[E]
+}
+
+func (e *factorError[E]) Error() string {
+	return fmt.Sprintf("found factor %s", e.g)
+}
+
+// inverse returns p^-1 mod h. If p is not invertible a factorError is
+// returned.
+func (r *endoRing[E]) inverse(p poly.Poly[E]) (poly.Poly[E], error) {
+	var inv, err = p.InverseMod(r.h)
+	var nie *poly.NotInvertibleError[E]
+
+	if errors.As(err, &nie) {
+		return inv, &factorError[E]{g: nie.GCD}
+	}
+
+	return inv, err
+}
+
+func (r *endoRing[E]) equal(p, q endo[E]) bool {
+	if p.zero || q.zero {
+		return p.zero == q.zero
+	}
+
+	return p.a.Equal(q.a) && p.b.Equal(q.b)
+}
+
+func (r *endoRing[E]) add(p, q endo[E]) (endo[E], error) {
+	var m, inv poly.Poly[E]
+	var err error
+
+	if p.zero {
+		return q, nil
+	}
+
+	if q.zero {
+		return p, nil
+	}
+
+	if p.a.Equal(q.a) {
+		if p.b.Add(q.b).IsZero() {
+			return endo[E]{zero: true}, nil
+		}
+
+		if !p.b.Equal(q.b) {
+			// p = q for some points and p = -q for others,
+			// so both b1 - b2 and b1 + b2 are zero divisors.
+			if _, err = r.inverse(p.b.Sub(q.b)); err == nil {
+				_, err = r.inverse(p.b.Add(q.b))
+			}
+			if err == nil {
+				// Only possible if h is not a factor of a
+				// division polynomial.
+				err = errors.New("points are neither equal " +
+					"nor opposite")
+			}
+
+			return endo[E]{}, err
+		}
+
+		return r.double(p)
+	}
+
+	// The slope is m(x)y with m = (b2 - b1) / (a2 - a1)
+	inv, err = r.inverse(q.a.Sub(p.a))
+	if err != nil {
+		return endo[E]{}, err
+	}
+
+	m = q.b.Sub(p.b).MulMod(inv, r.h)
+
+	return r.line(p, q, m), nil
+}
+
+func (r *endoRing[E]) double(p endo[E]) (endo[E], error) {
+	var num, den, inv poly.Poly[E]
+	var err error
+
+	// The slope is (3a^2 + A) / 2by = m(x)y with
+	// m = (3a^2 + A) / (2bf)
+	num = p.a.MulMod(p.a, r.h).Scale(r.c.F.FromInt64(3))
+	num = num.Add(poly.New(r.c.F, r.c.A))
+	den = p.b.MulMod(r.f, r.h).Scale(r.c.F.FromInt64(2))
+
+	inv, err = r.inverse(den)
+	if err != nil {
+		return endo[E]{}, err
+	}
+
+	return r.line(p, p, num.MulMod(inv, r.h)), nil
+}
+
+// line computes the third point of intersection, given the slope m(x)y.
+func (r *endoRing[E]) line(p, q endo[E], m poly.Poly[E]) endo[E] {
+	// x3 = m^2 y^2 - a1 - a2 = m^2 f - a1 - a2
+	// y3 = m y (a1 - x3) - b1 y
+	var x3 = m.MulMod(m, r.h).MulMod(r.f, r.h).Sub(p.a).Sub(q.a)
+	var y3 = m.MulMod(p.a.Sub(x3), r.h).Sub(p.b)
+
+	return endo[E]{a: x3, b: y3}
+}
+
+func (r *endoRing[E]) scalarM(k int64, p endo[E]) (endo[E], error) {
+	var res = endo[E]{zero: true}
+	var err error
+
+	for ; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			if res, err = r.add(res, p); err != nil {
+				return res, err
+			}
+		}
+
+		if k > 1 {
+			if p, err = r.add(p, p); err != nil {
+				return res, err
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// traceModL computes the trace of Frobenius mod l for an odd prime l.
+func (c *GenericCurve[E]) traceModL(dp *divisionPolys[E], l int64) (int64,
+	error) {
+	var h = dp.get(int(l))
+	var p = c.F.Modulus()
+
+	for {
+		var t, err = c.traceModH(h, p, l)
+		var fe *factorError[E]
+
+		if errors.As(err, &fe) {
+			// Continue with the smallest of the factors, any
+			// subset of the l-torsion points works.
+			var q, _ = h.DivMod(fe.g)
+
+			if q.Degree() < fe.g.Degree() {
+				h = q.Monic()
+			} else {
+				h = fe.g
+			}
+
+			continue
+		}
+
+		return t, err
+	}
+}
+
+func (c *GenericCurve[E]) traceModH(h poly.Poly[E], p *big.Int,
+	l int64) (int64, error) {
+	var r = endoRing[E]{
+		c: c,
+		h: h,
+		f: c.rhsPoly().Mod(h),
+	}
+	var x = poly.X(c.F)
+	var e = new(big.Int).Sub(p, one)
+	e.Rsh(e, 1)
+
+	// pi(x, y) = (x^p, y^p) = (x^p, f^((p-1)/2) y)
+	var pi = endo[E]{
+		a: x.PowMod(p, h),
+		b: r.f.PowMod(e, h),
+	}
+	// pi^2(x, y) = (x^p^2, y^p^2), y^p^2 = (f^((p-1)/2))^p y^p
+	var pi2 = endo[E]{
+		a: pi.a.PowMod(p, h),
+		b: pi.b.PowMod(p, h).MulMod(pi.b, h),
+	}
+	var id = endo[E]{
+		a: x.Mod(h),
+		b: poly.One(c.F),
+	}
+	var ql = new(big.Int).Mod(p, big.NewInt(l)).Int64()
+	var q, s endo[E]
+	var err error
+
+	if q, err = r.scalarM(ql, id); err != nil {
+		return 0, err
+	}
+
+	if s, err = r.add(pi2, q); err != nil {
+		return 0, err
+	}
+
+	if s.zero {
+		return 0, nil
+	}
+
+	// Find t such that t * pi = s
+	var tpi = pi
+	for t := int64(1); t < l; t++ {
+		if r.equal(tpi, s) {
+			return t, nil
+		}
+
+		if tpi, err = r.add(tpi, pi); err != nil {
+			return 0, err
+		}
+	}
+
+	return 0, fmt.Errorf("no trace found mod %d for %s", l, c)
+}
+
+// traceMod2 computes the trace of Frobenius mod 2. The trace is even
+// iff there is a point of order two, i.e iff x^3 + ax + b has a root.
+func (c *GenericCurve[E]) traceMod2() int64 {
+	var f = c.rhsPoly()
+	var x = poly.X(c.F)
+	var xp = x.PowMod(c.F.Modulus(), f)
+
+	// The roots of x^p - x are all elements in F_p.
+	if poly.GCD(xp.Sub(x), f).Degree() > 0 {
+		return 0
+	}
+
+	return 1
+}
+
+// Schoof counts the points on the curve using Schoof's algorithm.
+// Returned is the number of points, and the trace of Frobenius t,
+// where the number of points is p + 1 - t. An error is returned if the
+// trace can't be determined, e.g if the curve is singular.
+func (c *GenericCurve[E]) Schoof() (*big.Int, *big.Int, error) {
+	var p = c.F.Modulus()
+
+	if d := c.Discriminant(); d.Mod(d, p).Sign() == 0 {
+		return nil, nil, errors.New("curve is singular")
+	}
+	var dp = newDivisionPolys(c)
+	// Hasse: |t| <= 2*sqrt(p), collect residues until the product of
+	// the primes M satisfies M^2 > 16p.
+	var bound = new(big.Int).Mul(p, big.NewInt(16))
+	var m = big.NewInt(1)
+	var t = big.NewInt(0)
+
+	for l := int64(2); new(big.Int).Mul(m, m).Cmp(bound) <= 0; l++ {
+		var tl int64
+		var bl = big.NewInt(l)
+
+		if !bl.ProbablyPrime(0) || p.Cmp(bl) == 0 {
+			continue
+		}
+
+		if l == 2 {
+			tl = c.traceMod2()
+		} else {
+			var err error
+
+			if tl, err = c.traceModL(dp, l); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		t = crt(t, m, big.NewInt(tl), bl)
+		m.Mul(m, bl)
+	}
+
+	// Pick the representative closest to zero
+	var half = new(big.Int).Rsh(m, 1)
+	if t.Cmp(half) > 0 {
+		t.Sub(t, m)
+	}
+
+	var n = new(big.Int).Add(p, one)
+	n.Sub(n, t)
+
+	return n, t, nil
+}
+
+// crt returns x such that x = a1 mod m1 and x = a2 mod m2,
+// 0 <= x < m1*m2. m1 and m2 must be coprime.
+func crt(a1, m1, a2, m2 *big.Int) *big.Int {
+	// x = a1 + m1 * ((a2 - a1) * m1^-1 mod m2)
+	var inv = new(big.Int).ModInverse(m1, m2)
+	var k = new(big.Int).Sub(a2, a1)
+	var m = new(big.Int).Mul(m1, m2)
+
+	k.Mul(k, inv)
+	k.Mod(k, m2)
+
+	var x = new(big.Int).Mul(m1, k)
+	x.Add(x, a1)
+
+	return x.Mod(x, m)
+}
@@ -0,0 +1,79 @@
+package ec
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchoof(t *testing.T) {
+	var primes = []int64{5, 7, 11, 13, 17, 71, 101, 263, 1009}
+
+	for _, p := range primes {
+		for a := int64(0); a < 5; a++ {
+			for b := int64(0); b < 5; b++ {
+				var c, err = NewCurve(field.NewFinite(p), a, b)
+				if err != nil {
+					// Singular curves are rejected
+					var d = (&Curve{F: field.NewFinite(p), A: a,
+						B: b}).Discriminant()
+					assert.Zero(t, d.Mod(d, big.NewInt(p)).Sign())
+					continue
+				}
+
+				var exp = c.CountPointsLegendre()
+
+				var n, tr int64
+				n, tr, err = c.Schoof()
+				assert.Nil(t, err)
+
+				assert.Equal(t, exp, n, "wrong number of points for %s",
+					c.String())
+				assert.Equal(t, p+1-exp, tr, "wrong trace for %s",
+					c.String())
+			}
+		}
+	}
+}
+
+func TestSchoofDemoCurve(t *testing.T) {
+	var c = DemoCurve25
+	var n, err = c.CountPoints()
+
+	assert.Nil(t, err)
+	assert.Equal(t, c.N, n)
+
+	// Singular curves are not counted
+	_, err = (&Curve{F: field.NewFinite(23), A: 20, B: 2}).CountPoints()
+	assert.ErrorContains(t, err, "singular")
+}
+
+func TestSchoofBig(t *testing.T) {
+	// Same curve as DemoCurve25 but over a big.Int field
+	var c = DemoCurve25.Big()
+	var n, tr, err = c.Schoof()
+
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(DemoCurve25.N), n)
+	assert.Equal(t,
+		big.NewInt(DemoCurve25.F.P()+1-DemoCurve25.N), tr)
+
+	// y^2 = x^3 + 7 over a 32 bit prime
+	var p = new(big.Int).SetUint64(4294967291)
+	var bc *BigCurve
+
+	bc, err = NewGenericCurve[*big.Int](field.NewBigFinite(p),
+		big.NewInt(0), big.NewInt(7))
+	assert.Nil(t, err)
+
+	n, _, err = bc.Schoof()
+	assert.Nil(t, err)
+	// n * P must be the identity element for any point
+	for i := 0; i < 5; i++ {
+		var q = bc.RandomPoint()
+
+		assert.True(t, bc.ScalarM(n, q).Inf)
+	}
+}
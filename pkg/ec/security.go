@@ -0,0 +1,300 @@
+package ec
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	smath "github.com/kommendorkapten/sigsim/pkg/math"
+	"github.com/kommendorkapten/sigsim/pkg/poly"
+)
+
+// The thresholds used by SecurityReport, from SafeCurves. No curve over
+// an int64 field meets them, lower them to rank toy curves.
+var (
+	// MinRhoBits is the smallest accepted cost, in bits, of Pollard's
+	// rho on the curve. The twist is judged by TwistMargin.
+	MinRhoBits = 100.0
+	// MinCMBits is the smallest accepted bit length of the CM field
+	// discriminant.
+	MinCMBits = 100
+)
+
+// rigidBits is the largest bit length of a and b for the parameters to
+// be considered rigid.
+const rigidBits = 16
+
+// Criterion is the outcome of evaluating a single security criterion.
+type Criterion struct {
+	Name   string `json:"name"`
+	Safe   bool   `json:"safe"`
+	Detail string `json:"detail"`
+}
+
+// SecurityReport is the evaluation of a curve against the SafeCurves
+// criteria, see https://safecurves.cr.yp.to. Criteria which don't apply
+// to curves of this size, e.g indistinguishability, are not included.
+type SecurityReport struct {
+	Curve    string `json:"curve"`
+	P        int64  `json:"p"`
+	Order    int64  `json:"order"` // Number of points on the curve
+	N        int64  `json:"n"`     // Order of the generator
+	Cofactor int64  `json:"cofactor"`
+	Trace    int64  `json:"trace"`
+	// L is the largest prime factor of the number of points, the
+	// subgroup Pollard's rho has to solve the logarithm in.
+	L       int64   `json:"l"`
+	RhoBits float64 `json:"rho_bits"`
+	// TwistOrder is the number of points on the quadratic twist, and
+	// TwistL its largest prime factor.
+	TwistOrder   *big.Int `json:"twist_order"`
+	TwistL       *big.Int `json:"twist_l"`
+	TwistRhoBits float64  `json:"twist_rho_bits"`
+	// EmbeddingDegree is the order of p modulo L.
+	EmbeddingDegree int64    `json:"embedding_degree"`
+	CMDiscriminant  *big.Int `json:"cm_discriminant"`
+	Rigidity        string   `json:"rigidity"`
+	// Ladder is true if the curve has a Montgomery form, and Complete
+	// if it has a twisted Edwards form with a complete addition law.
+	Ladder   bool        `json:"ladder"`
+	Complete bool        `json:"complete"`
+	Criteria []Criterion `json:"criteria"`
+}
+
+// Safe returns true if all criteria are met.
+func (r *SecurityReport) Safe() bool {
+	for _, c := range r.Criteria {
+		if !c.Safe {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *SecurityReport) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "curve %s\n", r.Curve)
+	for _, c := range r.Criteria {
+		var status = "unsafe"
+
+		if c.Safe {
+			status = "safe"
+		}
+		fmt.Fprintf(&b, "%-18s %-6s %s\n", c.Name, status, c.Detail)
+	}
+
+	return b.String()
+}
+
+// add records the outcome of a criterion.
+func (r *SecurityReport) add(name string, safe bool, f string,
+	a ...any) {
+	r.Criteria = append(r.Criteria, Criterion{
+		Name:   name,
+		Safe:   safe,
+		Detail: fmt.Sprintf(f, a...),
+	})
+}
+
+// SecurityReport evaluates the curve against the SafeCurves criteria.
+// The number of points is computed with Schoof's algorithm, and the
+// orders are factored with Pollard's rho. An error is returned if the points can't be counted.
+// Rigidity can't be determined from the parameters alone. They are
+// considered somewhat rigid if both a and b are small, as they then
+// could not have been picked from a large set of candidates.
+func (c *Curve) SecurityReport() (*SecurityReport, error) {
+	var f = c.F
+	var p = f.P()
+	var order, err = c.CountPoints()
+	if err != nil {
+		return nil, err
+	}
+
+	var r = SecurityReport{
+		Curve: c.String(),
+		P:     p,
+		Order: order,
+		N:     c.N,
+	}
+
+	r.Trace = p + 1 - r.Order
+	r.L = largestFactor(big.NewInt(r.Order)).Int64()
+	r.RhoBits = rhoBits(big.NewInt(r.L))
+
+	r.add("field", big.NewInt(p).ProbablyPrime(32),
+		"p = %d", p)
+
+	// The generator must generate the subgroup of order L
+	var nPrime = big.NewInt(c.N).ProbablyPrime(32)
+	r.add("order", nPrime && c.N == r.L && c.ScalarM(c.N, c.G).Inf,
+		"n = %d, l = %d", c.N, r.L)
+
+	if c.N > 0 {
+		r.Cofactor = r.Order / c.N
+	}
+	// A cofactor divisible by l would give more than one subgroup of
+	// order l
+	r.add("cofactor", c.N > 0 && r.Order%c.N == 0 && r.Cofactor%r.L != 0,
+		"h = %d", r.Cofactor)
+
+	r.add("rho", r.RhoBits >= MinRhoBits,
+		"cost 2^%.1f, need 2^%.0f", r.RhoBits, MinRhoBits)
+
+	// Twist security, see Twist
+	if tw, err := c.Twist(); err != nil {
+		r.add("twist", false, "%s", err)
+	} else {
+		r.TwistOrder = big.NewInt(tw.Order)
+		r.TwistL = big.NewInt(tw.C.N)
+		r.TwistRhoBits = rhoBits(r.TwistL)
+		r.add("twist", !tw.Weak, "order %d, cost 2^%.1f",
+			r.TwistOrder, r.TwistRhoBits)
+	}
+
+	// Transfers to F_p^k (MOV) and to F_p (Smart). SafeCurves requires
+	// k >= (l - 1) / 100.
+	r.EmbeddingDegree = multOrder(p, r.L)
+	r.add("embedding degree", r.EmbeddingDegree >= (r.L-1)/100,
+		"k = %d", r.EmbeddingDegree)
+	r.add("anomalous", r.L != p, "l = %d, p = %d", r.L, p)
+
+	r.CMDiscriminant = cmDiscriminant(p, r.Trace)
+	r.add("cm discriminant", r.CMDiscriminant.BitLen() >= MinCMBits,
+		"D = %d, %d bits", r.CMDiscriminant, r.CMDiscriminant.BitLen())
+
+	var ab = minBits(f.Canonicalize(c.A), p)
+	var bb = minBits(f.Canonicalize(c.B), p)
+
+	r.Rigidity = "manipulable"
+	if ab <= rigidBits && bb <= rigidBits {
+		r.Rigidity = "somewhat rigid"
+	}
+	r.add("rigidity", r.Rigidity != "manipulable",
+		"%s, a has %d bits and b %d bits", r.Rigidity, ab, bb)
+
+	r.Ladder, r.Complete = c.forms()
+	r.add("ladder", r.Ladder, "montgomery form: %t", r.Ladder)
+	r.add("complete", r.Complete, "complete edwards form: %t",
+		r.Complete)
+
+	return &r, nil
+}
+
+// forms returns whether the curve has a Montgomery form, and a twisted
+// Edwards form with a complete addition law. See NewMontgomeryFromCurve
+// for the Montgomery form given by a root r with 3r^2 + a = s^2.
+// The Edwards form then has parameters 3r + 2s and 3r - 2s, swapped for
+// -s, and it's complete iff exactly one of them is a square.
+func (c *Curve) forms() (bool, bool) {
+	var f = c.F
+	var ladder, complete bool
+	var rhs = poly.New[int64](f, c.B, c.A, 0, 1)
+
+	for _, r := range rhs.Roots() {
+		var s, err = f.Sqrt(f.Add(f.Multiply(3, f.Multiply(r, r)), c.A))
+		if err != nil {
+			continue
+		}
+
+		ladder = true
+
+		var r3 = f.Multiply(3, r)
+		var l1 = f.Legendre(f.Add(r3, f.Multiply(2, s)))
+		var l2 = f.Legendre(f.Add(r3, f.Neg(f.Multiply(2, s))))
+
+		if l1*l2 == -1 {
+			complete = true
+		}
+	}
+
+	return ladder, complete
+}
+
+// largestFactor returns the largest prime factor of n > 1.
+func largestFactor(n *big.Int) *big.Int {
+	if n.Cmp(one) <= 0 {
+		return new(big.Int).Set(n)
+	}
+
+	var pf = smath.PrimeFactorsBig(n)
+
+	return pf[len(pf)-1]
+}
+
+// rhoBits returns log2 of the expected cost of Pollard's rho in a group
+// of prime order l, 0.886 * sqrt(l) additions.
+func rhoBits(l *big.Int) float64 {
+	var lf, _ = new(big.Float).SetInt(l).Float64()
+
+	return math.Log2(0.886) + math.Log2(lf)/2
+}
+
+// multOrder returns the multiplicative order of p modulo the prime l,
+// or zero if l divides p.
+func multOrder(p, l int64) int64 {
+	var bp, bl = big.NewInt(p), big.NewInt(l)
+
+	if l < 2 || p%l == 0 {
+		return 0
+	}
+
+	// Remove prime factors from l - 1 while p^k is still one
+	var k = l - 1
+	for _, q := range smath.PrimeFactors(l - 1) {
+		var e = big.NewInt(k / q)
+
+		if new(big.Int).Exp(bp, e, bl).Cmp(one) == 0 {
+			k /= q
+		}
+	}
+
+	return k
+}
+
+// cmDiscriminant returns the discriminant D of the CM field of a curve
+// with trace t, t^2 - 4p = s^2 D where D is 0 or 1 mod 4.
+func cmDiscriminant(p, t int64) *big.Int {
+	var d = new(big.Int).Mul(big.NewInt(t), big.NewInt(t))
+
+	d.Sub(d, new(big.Int).Lsh(big.NewInt(p), 2))
+	if d.Sign() == 0 {
+		return d
+	}
+
+	// The squarefree part of |t^2 - 4p|
+	var sf = big.NewInt(1)
+	var pf = smath.PrimeFactorsBig(new(big.Int).Neg(d))
+
+	// The factors are ordered, so equal factors are adjacent
+	for i := 0; i < len(pf); {
+		var j = i
+
+		for j < len(pf) && pf[j].Cmp(pf[i]) == 0 {
+			j++
+		}
+		if (j-i)%2 == 1 {
+			sf.Mul(sf, pf[i])
+		}
+		i = j
+	}
+
+	sf.Neg(sf)
+	if new(big.Int).Mod(sf, big.NewInt(4)).Int64() != 1 {
+		sf.Lsh(sf, 2)
+	}
+
+	return sf
+}
+
+// minBits returns the bit length of the representative of v mod p with
+// the smallest absolute value.
+func minBits(v, p int64) int {
+	if v > p/2 {
+		v = p - v
+	}
+
+	return big.NewInt(v).BitLen()
+}
@@ -0,0 +1,118 @@
+package ec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityReport(t *testing.T) {
+	var m, err = DemoMontgomery.Weierstrass()
+	assert.Nil(t, err)
+
+	var tests = []struct {
+		name   string
+		c      *Curve
+		unsafe []string
+	}{
+		{
+			name: "DemoCurve25",
+			c:    DemoCurve25,
+			unsafe: []string{"rho", "twist", "cm discriminant",
+				"rigidity", "ladder", "complete"},
+		},
+		{
+			name: "DemoSupersingular",
+			c:    DemoSupersingular,
+			// Trace zero, the twist has as many points
+			unsafe: []string{"rho", "embedding degree",
+				"cm discriminant"},
+		},
+		{
+			name: "DemoAnomalous",
+			c:    DemoAnomalous,
+			unsafe: []string{"rho", "twist", "embedding degree",
+				"anomalous", "cm discriminant", "rigidity", "ladder",
+				"complete"},
+		},
+		{
+			name:   "DemoMontgomery",
+			c:      m,
+			unsafe: []string{"rho", "cm discriminant", "rigidity"},
+		},
+	}
+
+	for _, tc := range tests {
+		var r, err = tc.c.SecurityReport()
+		var unsafe []string
+
+		assert.Nil(t, err, tc.name)
+
+		for _, c := range r.Criteria {
+			if !c.Safe {
+				unsafe = append(unsafe, c.Name)
+			}
+		}
+
+		assert.Equal(t, tc.unsafe, unsafe, tc.name)
+		assert.False(t, r.Safe(), tc.name)
+		assert.Equal(t, tc.c.F.P()+1-r.Trace, r.Order, tc.name)
+
+		// Same verdict on the twist as Twist
+		tw, err := tc.c.Twist()
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tw.Order, r.TwistOrder.Int64(), tc.name)
+		assert.Equal(t, tw.Weak, !r.Criteria[4].Safe, tc.name)
+	}
+
+	// Supersingular curves have trace zero, and D = -p for p = 3 mod 4
+	r, err := DemoSupersingular.SecurityReport()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), r.Trace)
+	assert.Equal(t, int64(4), r.Cofactor)
+	assert.Equal(t, int64(2), r.EmbeddingDegree)
+	assert.Equal(t, -DemoSupersingular.F.P(), r.CMDiscriminant.Int64())
+
+	// The curve is constructed with CM by Q(sqrt(-11))
+	r, err = DemoAnomalous.SecurityReport()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-11), r.CMDiscriminant.Int64())
+
+	// Montgomery curves have cofactor 8, and twist 4 times a prime
+	r, err = m.SecurityReport()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(8), r.Cofactor)
+	assert.Equal(t, DemoMontgomery.N, r.L)
+	assert.True(t, r.Ladder)
+	assert.True(t, r.Complete)
+}
+
+func TestSecurityReportJSON(t *testing.T) {
+	var r, err = DemoCurve25.SecurityReport()
+	assert.Nil(t, err)
+
+	var b []byte
+	b, err = json.Marshal(r)
+	assert.Nil(t, err)
+
+	var got SecurityReport
+	assert.Nil(t, json.Unmarshal(b, &got))
+	assert.Equal(t, r, &got)
+}
+
+func TestSecurityReportThresholds(t *testing.T) {
+	var rho, cm = MinRhoBits, MinCMBits
+	defer func() {
+		MinRhoBits, MinCMBits = rho, cm
+	}()
+
+	MinRhoBits, MinCMBits = 12, 20
+
+	// rho, twist and cm discriminant
+	var r, err = DemoCurve25.SecurityReport()
+	assert.Nil(t, err)
+	assert.True(t, r.Criteria[3].Safe)
+	assert.False(t, r.Criteria[4].Safe)
+	assert.True(t, r.Criteria[7].Safe)
+}
@@ -0,0 +1,123 @@
+package ec
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	smath "github.com/kommendorkapten/sigsim/pkg/math"
+)
+
+// TwistMargin is how many bits cheaper Pollard's rho may be on the
+// twist than on the curve, before the twist is considered weak by
+// Twist and SecurityReport.
+var TwistMargin = 2.0
+
+// QuadraticTwist is the quadratic twist of a curve,
+// y^2 = x^3 + ad^2x + bd^3 where d is a quadratic non-residue. An x
+// coordinate which is not on the curve is on the twist, so code that
+// only uses x coordinates, like the Montgomery ladder, can be fed
+// points on the twist. If the order of the twist has small factors,
+// such points leak the private key modulo those factors.
+type QuadraticTwist struct {
+	// C is the twist. G generates the subgroup of order N, the
+	// largest prime factor of the number of points.
+	C       *Curve
+	D       int64   // The non-residue
+	Trace   int64   // Trace of the original curve, the twist has -Trace
+	Order   int64   // Number of points on the twist, p + 1 + Trace
+	Factors []int64 // Prime factors of Order
+	// Weak is set if the logarithm is more than TwistMargin bits
+	// cheaper to solve on the twist than on the curve.
+	Weak bool
+
+	orig *Curve
+}
+
+// Twist returns the quadratic twist of the curve. The trace t of the
+// curve is computed with Schoof's algorithm, and the twist has
+// p + 1 + t points.
+func (c *Curve) Twist() (*QuadraticTwist, error) {
+	var f = c.F
+	var p = f.P()
+
+	if p == 2 {
+		return nil, errors.New("no quadratic non-residue mod 2")
+	}
+
+	var d = int64(2)
+	for f.Legendre(d) != -1 {
+		d++
+	}
+
+	var d2 = f.Multiply(d, d)
+	var tc, err = NewCurve(f, f.Multiply(c.A, d2),
+		f.Multiply(c.B, f.Multiply(d2, d)))
+	if err != nil {
+		return nil, err
+	}
+
+	var t = QuadraticTwist{
+		C:    tc,
+		D:    d,
+		orig: c,
+	}
+
+	var n int64
+	if n, t.Trace, err = c.Schoof(); err != nil {
+		return nil, err
+	}
+	t.Order = p + 1 + t.Trace
+	t.Factors = smath.PrimeFactors(t.Order)
+	tc.N = t.Factors[len(t.Factors)-1]
+	if tc.G, err = t.generator(); err != nil {
+		return nil, err
+	}
+
+	var l = smath.PrimeFactors(n)
+	t.Weak = rhoBits(big.NewInt(tc.N))+TwistMargin <
+		rhoBits(big.NewInt(l[len(l)-1]))
+
+	return &t, nil
+}
+
+// generator returns a point of order N on the twist.
+func (t *QuadraticTwist) generator() (Point, error) {
+	var c = t.C
+	var h = t.Order / c.N
+
+	for x := int64(0); x < c.F.P(); x++ {
+		var y, err = c.Y(x)
+		if err != nil {
+			continue
+		}
+
+		var g = c.ScalarM(h, Point{X: x, Y: y})
+		if !g.Inf {
+			return g, nil
+		}
+	}
+
+	return Point{}, errors.New("no generator found")
+}
+
+// Point returns the point on the twist for an x coordinate which is not
+// on the original curve. If x^3 + ax + b is not a square, d times it is,
+// and (dx)^3 + ad^2(dx) + bd^3 = d^3(x^3 + ax + b). If x is on the
+// curve an error is returned.
+func (t *QuadraticTwist) Point(x int64) (Point, error) {
+	var f = t.C.F
+	var v = t.orig.rhs(f.Canonicalize(x))
+
+	if f.Legendre(v) != -1 {
+		return Point{}, fmt.Errorf("x %d is on the curve", x)
+	}
+
+	var dx = f.Multiply(t.D, x)
+	var y, err = t.C.Y(dx)
+	if err != nil {
+		return Point{}, err
+	}
+
+	return Point{X: dx, Y: y}, nil
+}
@@ -0,0 +1,80 @@
+package ec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwist(t *testing.T) {
+	var m, err = DemoMontgomery.Weierstrass()
+	assert.Nil(t, err)
+
+	var tests = []struct {
+		name  string
+		c     *Curve
+		order int64
+		n     int64
+		weak  bool
+	}{
+		{
+			name:  "DemoCurve25",
+			c:     DemoCurve25,
+			order: 2*DemoCurve25.F.P() + 2 - DemoCurve25.N,
+			n:     4597,
+			weak:  true,
+		},
+		{
+			// The twist of Curve25519 has 4 times a prime points
+			name:  "DemoMontgomery",
+			c:     m,
+			order: 2*m.F.P() + 2 - 8*m.N,
+			n:     (2*m.F.P() + 2 - 8*m.N) / 4,
+			weak:  false,
+		},
+	}
+
+	for _, tc := range tests {
+		var tw, err = tc.c.Twist()
+		assert.Nil(t, err, tc.name)
+
+		assert.Equal(t, tc.order, tw.Order, tc.name)
+		assert.Equal(t, tc.n, tw.C.N, tc.name)
+		assert.Equal(t, tc.weak, tw.Weak, tc.name)
+		assert.Equal(t, -1, tc.c.F.Legendre(tw.D), tc.name)
+
+		assert.True(t, tw.C.Valid(tw.C.G), tc.name)
+		assert.False(t, tw.C.G.Inf, tc.name)
+		assert.True(t, tw.C.ScalarM(tw.C.N, tw.C.G).Inf, tc.name)
+
+		var n int64
+		n, err = tw.C.CountPoints()
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tw.Order, n, tc.name)
+	}
+}
+
+func TestTwistPoint(t *testing.T) {
+	var c = DemoCurve25
+	var tw, err = c.Twist()
+	assert.Nil(t, err)
+
+	var found int
+	for x := int64(1); found < 5; x++ {
+		var p Point
+
+		if _, err = c.Y(x); err == nil {
+			_, err = tw.Point(x)
+			assert.NotNil(t, err, x)
+
+			continue
+		}
+
+		p, err = tw.Point(x)
+		assert.Nil(t, err, x)
+		assert.True(t, tw.C.Valid(p), x)
+		assert.False(t, c.Valid(Point{X: x, Y: p.Y}), x)
+		assert.True(t, tw.C.ScalarM(tw.Order, p).Inf, x)
+		found++
+	}
+}
@@ -0,0 +1,157 @@
+package ec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MinEmbeddingDegree is the smallest embedding degree accepted by
+// Verify. With a small embedding degree k, the discrete logarithm
+// problem can be moved to F_p^k with the MOV attack, where
+// sub-exponential algorithms exist.
+var MinEmbeddingDegree = 20
+
+// Check is the outcome of a single check performed by VerifyReport.
+// Err is nil if the check passed.
+type Check struct {
+	Name string
+	Err  error
+}
+
+// Report is the result of verifying a curve. All checks are always
+// performed, so every problem with the curve is reported, not just the
+// first one.
+type Report struct {
+	Checks []Check
+	// EmbeddingDegree is the embedding degree of the curve, or zero if
+	// it's at least MinEmbeddingDegree.
+	EmbeddingDegree int
+}
+
+// Failed returns the checks that did not pass.
+func (r *Report) Failed() []Check {
+	var failed []Check
+
+	for _, c := range r.Checks {
+		if c.Err != nil {
+			failed = append(failed, c)
+		}
+	}
+
+	return failed
+}
+
+// Err returns nil if all checks passed, otherwise an error wrapping the
+// errors of all failed checks.
+func (r *Report) Err() error {
+	var errs []error
+
+	for _, c := range r.Failed() {
+		errs = append(errs, c.Err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *Report) String() string {
+	var b strings.Builder
+
+	for _, c := range r.Checks {
+		var status = "ok"
+
+		if c.Err != nil {
+			status = c.Err.Error()
+		}
+		fmt.Fprintf(&b, "%-18s %s\n", c.Name, status)
+	}
+
+	return b.String()
+}
+
+// add records the outcome of a check.
+func (r *Report) add(name string, err error) {
+	r.Checks = append(r.Checks, Check{Name: name, Err: err})
+}
+
+// Verify verifies all the parameters of the curve. The returned error
+// holds all the problems found, see VerifyReport.
+func (c *GenericCurve[E]) Verify() error {
+	return c.VerifyReport().Err()
+}
+
+// VerifyReport verifies all the parameters of the curve, and returns a
+// report with the outcome of each check.
+func (c *GenericCurve[E]) VerifyReport() *Report {
+	var r Report
+	var err error
+
+	// Is the underlying field a prime
+	var p = c.F.Modulus()
+	if !p.ProbablyPrime(256) {
+		err = fmt.Errorf("field order %d is not prime", p)
+	}
+	r.add("field", err)
+
+	// The discriminant must be non-zero in the field
+	err = nil
+	var d = c.Discriminant()
+	if d.Mod(d, p).Sign() == 0 {
+		err = fmt.Errorf("discriminant is zero")
+	}
+	r.add("discriminant", err)
+
+	// Is generator point on curve
+	err = nil
+	if !c.Valid(c.G) {
+		err = fmt.Errorf("generator point is not on curve")
+	} else if c.G.Inf {
+		err = fmt.Errorf("generator point is the identity element")
+	}
+	r.add("generator", err)
+
+	var n = c.F.Int(c.N)
+
+	err = nil
+	if !n.ProbablyPrime(256) {
+		err = fmt.Errorf("curve order %v is not prime", c.N)
+	}
+	r.add("order", err)
+
+	// Verify order of the point. As N is prime, N*G being the identity
+	// element means that the order of G is exactly N. This avoids
+	// having to compute the order, which is not feasible for large
+	// curves.
+	err = nil
+	if !c.ScalarM(c.N, c.G).Inf {
+		err = fmt.Errorf("invalid order for generator point, expected %v",
+			c.N)
+	}
+	r.add("generator order", err)
+
+	// Verify the bitsize of the order
+	err = nil
+	if n.BitLen() != c.BS {
+		err = fmt.Errorf("bitlength %d for curver order does not match %d",
+			n.BitLen(), c.BS)
+	}
+	r.add("bitlength", err)
+
+	// MOV attack
+	err = nil
+	r.EmbeddingDegree = c.EmbeddingDegree(MinEmbeddingDegree - 1)
+	if r.EmbeddingDegree != 0 {
+		err = fmt.Errorf("embedding degree %d is less than %d",
+			r.EmbeddingDegree, MinEmbeddingDegree)
+	}
+	r.add("embedding degree", err)
+
+	// Smart's attack
+	err = nil
+	if n.Cmp(p) == 0 {
+		err = fmt.Errorf("curve is anomalous, the order is %v", p)
+	}
+	r.add("anomalous", err)
+
+	return &r
+}
@@ -0,0 +1,43 @@
+package ec
+
+import (
+	"testing"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyReport(t *testing.T) {
+	var r = DemoCurve25.VerifyReport()
+
+	assert.Nil(t, r.Err())
+	assert.Empty(t, r.Failed())
+	assert.Equal(t, 0, r.EmbeddingDegree)
+
+	// Supersingular curves have embedding degree at most six
+	r = DemoSupersingular.VerifyReport()
+	assert.NotNil(t, r.Err())
+	assert.Equal(t, 2, r.EmbeddingDegree)
+
+	var names []string
+	for _, c := range r.Failed() {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"embedding degree"}, names)
+	assert.Contains(t, r.String(), "embedding degree 2 is less than")
+
+	// y^2 = x^3 - 3x + 2 = (x - 1)^2(x + 2) is singular, but with
+	// a = p - 3 the discriminant is only zero mod p.
+	var sc = &Curve{F: field.NewFinite(23), A: 20, B: 2}
+	assert.NotZero(t, sc.Discriminant().Sign())
+	assert.Equal(t, "discriminant", sc.VerifyReport().Failed()[0].Name)
+	assert.ErrorContains(t, sc.Verify(), "discriminant is zero")
+
+	// All problems are reported, not only the first
+	var c = *DemoCurve25
+	c.G = Point{X: 1, Y: 1}
+	c.N = 33480828
+	assert.Len(t, c.VerifyReport().Failed(), 3)
+	assert.ErrorContains(t, c.Verify(), "not on curve")
+	assert.ErrorContains(t, c.Verify(), "not prime")
+}
@@ -0,0 +1,225 @@
+package ec
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// DefaultWindow is the window size used when the curve's Window is not
+// set.
+var DefaultWindow = 4
+
+// maxFixedBases is the number of fixed-base tables kept in the cache.
+// When full, the cache is emptied.
+const maxFixedBases = 64
+
+// fixedBases caches the fixed-base tables, keyed by the curve
+// parameters and window size, see fixedBaseKey. The tables are kept
+// outside of the curves so a curve can be copied or modified freely.
+var fixedBases = struct {
+	sync.Mutex
+	m map[string]any
+}{m: map[string]any{}}
+
+// fixedBase is a precomputed table for the comb method, for a base
+// point g. The scalar is split into w rows of d bits, and
+// t[i] = sum(2^(j*d) * g) for all bits j set in i.
+type fixedBase[E any] struct {
+	w int
+	d int
+	t []JacobianPoint[E]
+}
+
+// window returns the window size to use, between 2 and 12.
+func (c *GenericCurve[E]) window() int {
+	if c.Window < 2 || c.Window > 12 {
+		return DefaultWindow
+	}
+
+	return c.Window
+}
+
+// wnaf returns the width-w non-adjacent form of k, least significant
+// digit first. Every non-zero digit is odd and less than 2^(w-1) in
+// absolute value, and any w consecutive digits contain at most one
+// non-zero digit.
+func wnaf(k *big.Int, w int) []int {
+	var digits []int
+	var mod = big.NewInt(1 << w)
+	var half = 1 << (w - 1)
+	var r = new(big.Int).Set(k)
+	var m = new(big.Int)
+
+	for r.Sign() > 0 {
+		var d = 0
+
+		if r.Bit(0) == 1 {
+			d = int(m.Mod(r, mod).Int64())
+			if d >= half {
+				d -= 1 << w
+			}
+			r.Sub(r, big.NewInt(int64(d)))
+		}
+
+		digits = append(digits, d)
+		r.Rsh(r, 1)
+	}
+
+	return digits
+}
+
+// negJacobian returns -p.
+func (c *GenericCurve[E]) negJacobian(p JacobianPoint[E]) JacobianPoint[E] {
+	return JacobianPoint[E]{X: p.X, Y: c.F.Neg(p.Y), Z: p.Z}
+}
+
+// wnafM computes kp using the wNAF representation of k. The odd
+// multiples p, 3p, ... (2^(w-1) - 1)p are precomputed, which reduces
+// the number of additions to about one for every w + 1 bits.
+func (c *GenericCurve[E]) wnafM(k *big.Int,
+	p JacobianPoint[E]) JacobianPoint[E] {
+	var w = c.window()
+	var digits = wnaf(k, w)
+	var odd = make([]JacobianPoint[E], 1<<(w-2))
+	var r = c.ToJacobian(GenericPoint[E]{Inf: true})
+
+	if len(digits) == 0 {
+		return r
+	}
+
+	var p2 = c.double(p)
+
+	odd[0] = p
+	for i := 1; i < len(odd); i++ {
+		odd[i] = c.add(odd[i-1], p2)
+	}
+
+	for i := len(digits) - 1; i >= 0; i-- {
+		r = c.double(r)
+
+		switch d := digits[i]; {
+		case d > 0:
+			r = c.add(r, odd[d/2])
+		case d < 0:
+			r = c.add(r, c.negJacobian(odd[-d/2]))
+		}
+	}
+
+	return r
+}
+
+// hasG returns false if the generator is not set on a BigCurve, i.e
+// its coordinates are nil.
+func (c *GenericCurve[E]) hasG() bool {
+	var x any = c.G.X
+
+	if bx, ok := x.(*big.Int); ok && bx == nil {
+		return false
+	}
+
+	return true
+}
+
+// fixedBaseKey returns the key for the table for G with window size
+// w. The element type is part of the key, as the same curve can be
+// used with different field backends.
+func (c *GenericCurve[E]) fixedBaseKey(w int) string {
+	return fmt.Sprintf("%T:%s:%s:%s:%s:%d", c.A, c.F.Modulus(),
+		c.F.Int(c.A), c.F.Int(c.B), c.pointKey(c.G), w)
+}
+
+// cachedFixedBase returns the cached table for G, or nil if there is
+// none.
+func (c *GenericCurve[E]) cachedFixedBase() *fixedBase[E] {
+	var key = c.fixedBaseKey(c.window())
+
+	fixedBases.Lock()
+	defer fixedBases.Unlock()
+
+	var fb, _ = fixedBases.m[key].(*fixedBase[E])
+
+	return fb
+}
+
+// fixedBaseTable returns the comb table for G, creating it if needed.
+// The table is built without holding the lock, so concurrent callers
+// may build it more than once.
+func (c *GenericCurve[E]) fixedBaseTable() *fixedBase[E] {
+	var w = c.window()
+
+	if fb := c.cachedFixedBase(); fb != nil {
+		return fb
+	}
+
+	// Cover all scalars less than the order of any point
+	var bits = c.F.Modulus().BitLen() + 1
+	var fb = fixedBase[E]{
+		w: w,
+		d: (bits + w - 1) / w,
+		t: make([]JacobianPoint[E], 1<<w),
+	}
+	var rows = make([]GenericPoint[E], w)
+
+	// rows[j] = 2^(j*d) * G
+	rows[0] = c.G
+	for j := 1; j < w; j++ {
+		var r = c.ToJacobian(rows[j-1])
+
+		for i := 0; i < fb.d; i++ {
+			r = c.DoubleJacobian(r)
+		}
+		rows[j] = c.FromJacobian(r)
+	}
+
+	// The entries are kept affine, so the additions in the comb are
+	// mixed additions.
+	fb.t[0] = c.ToJacobian(GenericPoint[E]{Inf: true})
+	for i := 1; i < len(fb.t); i++ {
+		var r = c.ToJacobian(GenericPoint[E]{Inf: true})
+
+		for j := 0; j < w; j++ {
+			if i&(1<<j) != 0 {
+				r = c.AddJacobian(r, c.ToJacobian(rows[j]))
+			}
+		}
+		fb.t[i] = c.ToJacobian(c.FromJacobian(r))
+	}
+
+	fixedBases.Lock()
+	if len(fixedBases.m) >= maxFixedBases {
+		fixedBases.m = map[string]any{}
+	}
+	fixedBases.m[c.fixedBaseKey(w)] = &fb
+	fixedBases.Unlock()
+
+	return &fb
+}
+
+// combM computes kG using the fixed-base comb method. Only d doublings
+// and at most d additions are needed, where d is the bit length of the
+// order divided by the window size. ok is false if k is too large for
+// the table.
+func (c *GenericCurve[E]) combM(k *big.Int) (JacobianPoint[E], bool) {
+	var fb = c.fixedBaseTable()
+	var r = c.ToJacobian(GenericPoint[E]{Inf: true})
+
+	if k.BitLen() > fb.d*fb.w {
+		return r, false
+	}
+
+	for col := fb.d - 1; col >= 0; col-- {
+		var idx = 0
+
+		r = c.double(r)
+		for j := 0; j < fb.w; j++ {
+			idx |= int(k.Bit(j*fb.d+col)) << j
+		}
+
+		if idx != 0 {
+			r = c.add(r, fb.t[idx])
+		}
+	}
+
+	return r, true
+}
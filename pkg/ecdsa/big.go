@@ -0,0 +1,189 @@
+package ecdsa
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/kommendorkapten/sigsim/pkg/ec"
+	"github.com/kommendorkapten/sigsim/pkg/field"
+)
+
+// This file implements ECDSA over curves with big.Int backed fields,
+// e.g the standard curves ec.P256, ec.P384 and ec.Secp256k1. The int64
+// based keys are converted and use the same implementation.
+
+// BigPrivateKey on an elliptic curve over a big.Int field.
+type BigPrivateKey struct {
+	Pub *BigPublicKey
+	D   *big.Int
+}
+
+// BigPublicKey for a key on an elliptic curve over a big.Int field.
+type BigPublicKey struct {
+	C *ec.BigCurve
+	P ec.BigPoint
+}
+
+func generateBigKey(c *ec.BigCurve, d *big.Int) *BigPrivateKey {
+	var pub = BigPublicKey{
+		C: c,
+		P: c.ScalarM(d, c.G),
+	}
+	var p = BigPrivateKey{
+		Pub: &pub,
+		D:   d,
+	}
+
+	return &p
+}
+
+// randScalar returns a random integer in [1, n).
+func randScalar(r io.Reader, n *big.Int) (*big.Int, error) {
+	for {
+		var k, err = rand.Int(r, n)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate random number %w", err)
+		}
+
+		if k.Sign() > 0 {
+			return k, nil
+		}
+	}
+}
+
+// GenerateBigKey returns a randomly generated private key on the
+// provided curve.
+func GenerateBigKey(c *ec.BigCurve, r io.Reader) (*BigPrivateKey, error) {
+	var d, err = randScalar(r, c.N)
+	if err != nil {
+		return nil, err
+	}
+
+	return generateBigKey(c, d), nil
+}
+
+// SignBig signs a message. Returned is the r and s values.
+func SignBig(r io.Reader, p *BigPrivateKey, h []byte) (*big.Int, *big.Int,
+	error) {
+	var k, err = randScalar(r, p.Pub.C.N)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rawSignBig(p, k, h)
+}
+
+// Calculate a signature given a k and a digest:
+// z = h truncated to the curve's bitlength
+// p = k*G
+// r = p.X mod N
+// s = (z + r*d)/k mod N
+// If p is the identity element or r is congruent to 0 mod N, error is
+// returned
+// If k does not invertible mod N, error is returned
+// If s is 0, error is returned
+// The signature pair (r, s) is returned.
+func rawSignBig(pk *BigPrivateKey, k *big.Int, h []byte) (*big.Int,
+	*big.Int, error) {
+	// Signatures are computed mod N
+	var sf = field.NewBigFinite(pk.Pub.C.N)
+	var z = sf.Canonicalize(truncateBig(h, pk.Pub.C.BS))
+	var p = pk.Pub.C.ScalarM(k, pk.Pub.C.G)
+	var r, s, inv *big.Int
+	var err error
+
+	if p.Inf {
+		return nil, nil, errInvK
+	}
+
+	if r = sf.Canonicalize(p.X); r.Sign() == 0 {
+		return nil, nil, errInvK
+	}
+
+	// This shouldn't happen if N is prime
+	if inv, err = sf.Inverse(k); err != nil {
+		return nil, nil, errInvK
+	}
+
+	s = sf.Multiply(inv, sf.Add(z, sf.Multiply(r, pk.D)))
+	if s.Sign() == 0 {
+		return nil, nil, errInvK
+	}
+
+	return r, s, nil
+}
+
+// VerifyBig verifies a signature (r and s) for a give message.
+func VerifyBig(pub *BigPublicKey, r, s *big.Int, h []byte) bool {
+	// Signatures are compute mod N
+	var sf = field.NewBigFinite(pub.C.N)
+	var z, u1, u2, inv *big.Int
+	var cp ec.BigPoint
+	var err error
+
+	// Public key must not be the identity element
+	if pub.P.Inf {
+		return false
+	}
+	// Point must be on the curve
+	if !pub.C.Valid(pub.P) {
+		return false
+	}
+	// order * point must be the identity element
+	var q = pub.C.ScalarM(pub.C.N, pub.P)
+	if !q.Inf {
+		return false
+	}
+
+	// Public key is valid, verify the signature
+	if r.Sign() < 1 || r.Cmp(pub.C.N) >= 0 {
+		return false
+	}
+
+	if s.Sign() < 1 || s.Cmp(pub.C.N) >= 0 {
+		return false
+	}
+
+	z = sf.Canonicalize(truncateBig(h, pub.C.BS))
+
+	inv, err = sf.Inverse(s)
+	if err != nil {
+		return false
+	}
+
+	u1 = sf.Multiply(z, inv)
+	u2 = sf.Multiply(r, inv)
+
+	cp = pub.C.MultiScalarM([]*big.Int{u1, u2}, []ec.BigPoint{pub.C.G, pub.P})
+	if cp.Inf {
+		return false
+	}
+
+	// Signature is valid if cp.X is congruent to r mod N
+	// cp is calculated over the curve of order P which may be higher
+	// than the sub-field N
+	return sf.Canonicalize(cp.X).Cmp(r) == 0
+}
+
+// truncateBig treats b as a big endian integer.
+// Returns the bs most significant bits. If b is shorter than bs bits,
+// all of b is used.
+func truncateBig(b []byte, bs int) *big.Int {
+	var z = new(big.Int)
+	var nb = (bs + 7) / 8
+
+	if len(b) > nb {
+		b = b[:nb]
+	}
+
+	z.SetBytes(b)
+
+	if excess := len(b)*8 - bs; excess > 0 {
+		// Rotate right to get rid of the extra bits
+		z.Rsh(z, uint(excess))
+	}
+
+	return z
+}
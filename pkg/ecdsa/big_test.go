@@ -0,0 +1,74 @@
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/kommendorkapten/sigsim/pkg/ec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateBig(t *testing.T) {
+	var h = []byte{0xab, 0xcd, 0xef}
+
+	assert.Equal(t, int64(0x2af), truncateBig(h, 10).Int64())
+	assert.Equal(t, truncate(h, 13), truncateBig(h, 13).Int64())
+	// Short digests are used as is
+	assert.Equal(t, int64(0xabcdef), truncateBig(h, 256).Int64())
+}
+
+// Sign and verify on the exact same curves as crypto/ecdsa.
+func TestCompatBig(t *testing.T) {
+	var tests = []struct {
+		c   *ec.BigCurve
+		ref elliptic.Curve
+	}{
+		{c: ec.P256, ref: elliptic.P256()},
+		{c: ec.P384, ref: elliptic.P384()},
+		// Not in the standard library, the deprecated generic
+		// implementation is used with our arithmetic.
+		{c: ec.Secp256k1, ref: ec.NewCompatCurve(ec.Secp256k1)},
+	}
+
+	for _, tc := range tests {
+		var name = tc.ref.Params().Name
+		var h = sha256.Sum256([]byte("you can't edit this"))
+		var pk1, err = GenerateBigKey(tc.c, rand.Reader)
+		assert.Nil(t, err)
+		var pk2 = ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: tc.ref,
+				X:     pk1.Pub.P.X,
+				Y:     pk1.Pub.P.Y,
+			},
+			D: pk1.D,
+		}
+
+		// Sign with ecdsa and verify with VerifyBig
+		r, s, err := ecdsa.Sign(rand.Reader, &pk2, h[:])
+		assert.Nil(t, err, name)
+		assert.True(t, VerifyBig(pk1.Pub, r, s, h[:]), name)
+
+		// Sign with SignBig and verify with ecdsa
+		h = sha256.Sum256([]byte("this is too immutable"))
+		r, s, err = SignBig(rand.Reader, pk1, h[:])
+		assert.Nil(t, err, name)
+		assert.True(t, ecdsa.Verify(&pk2.PublicKey, h[:], r, s), name)
+		assert.True(t, VerifyBig(pk1.Pub, r, s, h[:]), name)
+
+		// Wrong message
+		h = sha256.Sum256([]byte("a forgery"))
+		assert.False(t, VerifyBig(pk1.Pub, r, s, h[:]), name)
+
+		// Non-canonical public key, rejected by crypto/elliptic too
+		h = sha256.Sum256([]byte("this is too immutable"))
+		var pub = *pk1.Pub
+		pub.P.X = new(big.Int).Add(pub.P.X, tc.c.F.Modulus())
+		assert.False(t, VerifyBig(&pub, r, s, h[:]), name)
+		assert.False(t, tc.ref.IsOnCurve(pub.P.X, pub.P.Y), name)
+	}
+}
@@ -1,6 +1,7 @@
 package ecdsa
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/sha256"
@@ -167,6 +168,11 @@ func TestSign(t *testing.T) {
 
 		assert.NotEqual(t, r1, r2)
 		assert.NotEqual(t, s1, s2)
+
+		// Same point, but with a non-canonical x coordinate
+		var pub = *p.Pub
+		pub.P.X += pub.C.F.P()
+		assert.False(t, Verify(&pub, r1, s1, h[:]))
 	})
 }
 
@@ -220,6 +226,48 @@ func TestSolve(t *testing.T) {
 	}
 }
 
+func TestRecoverAnomalous(t *testing.T) {
+	var p, err = GenerateKey(ec.DemoAnomalous, rand.Reader)
+	assert.Nil(t, err)
+
+	rec, err := RecoverAnomalous(p.Pub)
+	assert.Nil(t, err)
+	assert.Equal(t, p.D, rec.D)
+
+	var h = sha256.Sum256([]byte("forged message"))
+	r, s, err := Sign(rand.Reader, rec, h[:])
+	assert.Nil(t, err)
+	assert.True(t, Verify(p.Pub, r, s, h[:]))
+
+	_, err = RecoverAnomalous(generateKey(ec.DemoCurve25, 847079).Pub)
+	assert.NotNil(t, err)
+}
+
+func TestRecoverDLP(t *testing.T) {
+	var p, err = GenerateKey(ec.DemoCurve25, rand.Reader)
+	assert.Nil(t, err)
+
+	var c = p.Pub.C
+	d1, err := c.SolveDLP(context.Background(), c.G, p.Pub.P, c.N)
+	assert.Nil(t, err)
+	d2, err := c.Rho(context.Background(), c.G, p.Pub.P, c.N, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, p.D, d1)
+	assert.Equal(t, p.D, d2)
+}
+
+func TestRecoverSmooth(t *testing.T) {
+	// The order of G is smooth, the key is recovered with Pohlig-Hellman
+	var p, err = GenerateKey(ec.DemoSmooth, rand.Reader)
+	assert.Nil(t, err)
+
+	var c = p.Pub.C
+	d, err := c.PohligHellman(context.Background(), c.G, p.Pub.P, c.N)
+	assert.Nil(t, err)
+	assert.Equal(t, p.D, d)
+}
+
 // This test relies on deprecated functions in crypto/elliptic
 // This is only included to make sure that sign/verify is compatible
 // a known verified implementation.
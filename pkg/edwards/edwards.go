@@ -0,0 +1,377 @@
+// Package edwards implements arithmetic on twisted Edwards curves over
+// a finite field, ax^2 + y^2 = 1 + dx^2y^2.
+// Every twisted Edwards curve is birationally equivalent to a
+// Montgomery curve, and thus to a Weierstrass curve, so the curves can
+// be converted to an ec.Curve.
+package edwards
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kommendorkapten/sigsim/pkg/ec"
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	smath "github.com/kommendorkapten/sigsim/pkg/math"
+)
+
+// Point represents a point on a twisted Edwards curve. Unlike for
+// Weierstrass curves, the identity element is an ordinary point,
+// (0, 1).
+type Point struct {
+	X int64
+	Y int64
+}
+
+// Identity is the identity element.
+var Identity = Point{X: 0, Y: 1}
+
+// Curve is a twisted Edwards curve ax^2 + y^2 = 1 + dx^2y^2.
+type Curve struct {
+	F *field.Finite
+	A int64 // A parameter
+	D int64 // D parameter
+	G Point // Generator point
+	N int64 // Order of the generator point
+	H int64 // Cofactor, the number of points divided by N
+}
+
+// DemoCurve is a toy curve with the same shape as Ed25519, a = -1 and
+// d = -121666/121665. Its Montgomery form has A = -486662, so with
+// u -> -u it's birationally equivalent to ec.DemoMontgomery. Note that
+// Ed25519 uses d = -121665/121666, which here would give the twist.
+var DemoCurve = &Curve{
+	F: field.NewFinite(2147352797),
+	A: 2147352796,
+	D: 259592034,
+	G: Point{
+		X: 1456069124,
+		Y: 1288411689,
+	},
+	N: 268419413,
+	H: 8,
+}
+
+// New returns the twisted Edwards curve ax^2 + y^2 = 1 + dx^2y^2. a and
+// d must be distinct and non-zero.
+func New(f *field.Finite, a, d int64) (*Curve, error) {
+	var c = Curve{
+		F: f,
+		A: f.Canonicalize(a),
+		D: f.Canonicalize(d),
+	}
+
+	if c.A == 0 || c.D == 0 || c.A == c.D {
+		return nil, fmt.Errorf(
+			"provided parameters are not valid, a:%d d:%d",
+			a, d,
+		)
+	}
+
+	return &c, nil
+}
+
+func (c *Curve) String() string {
+	return fmt.Sprintf("%d %d %d %+v %d %d",
+		c.F.P(),
+		c.A,
+		c.D,
+		c.G,
+		c.N,
+		c.H,
+	)
+}
+
+// Complete returns true if the addition law is complete, i.e it's
+// defined for all pairs of points. This is the case when a is a square
+// and d is not.
+func (c *Curve) Complete() bool {
+	return c.F.Legendre(c.A) == 1 && c.F.Legendre(c.D) == -1
+}
+
+// Valid returns true if the provided point is on the curve.
+func (c *Curve) Valid(p Point) bool {
+	var f = c.F
+	var x2 = f.Multiply(p.X, p.X)
+	var y2 = f.Multiply(p.Y, p.Y)
+	var lhs = f.Add(f.Multiply(c.A, x2), y2)
+	var rhs = f.Add(1, f.Multiply(c.D, f.Multiply(x2, y2)))
+
+	return lhs == rhs
+}
+
+// Neg returns the inverse of p, (-x, y).
+func (c *Curve) Neg(p Point) Point {
+	return Point{X: c.F.Neg(p.X), Y: c.F.Canonicalize(p.Y)}
+}
+
+// Add two points together and returns the resulting point. The same
+// formula is used for doubling and for the identity element:
+// x3 = (x1y2 + y1x2) / (1 + dx1x2y1y2)
+// y3 = (y1y2 - ax1x2) / (1 - dx1x2y1y2)
+// If the curve is not complete, the denominators may be zero, in which
+// case Add panics.
+func (c *Curve) Add(p, q Point) Point {
+	var f = c.F
+	var x1x2 = f.Multiply(p.X, q.X)
+	var y1y2 = f.Multiply(p.Y, q.Y)
+	var t = f.Multiply(c.D, f.Multiply(x1x2, y1y2))
+	var xn = f.Add(f.Multiply(p.X, q.Y), f.Multiply(p.Y, q.X))
+	var yn = f.Add(y1y2, f.Neg(f.Multiply(c.A, x1x2)))
+	var xd, yd int64
+	var err error
+
+	if xd, err = f.Inverse(f.Add(1, t)); err != nil {
+		panic(fmt.Sprintf("exceptional points %+v %+v", p, q))
+	}
+
+	if yd, err = f.Inverse(f.Add(1, f.Neg(t))); err != nil {
+		panic(fmt.Sprintf("exceptional points %+v %+v", p, q))
+	}
+
+	return Point{
+		X: f.Multiply(xn, xd),
+		Y: f.Multiply(yn, yd),
+	}
+}
+
+// ScalarM calculates the scalar multiplication of a point.
+func (c *Curve) ScalarM(k int64, p Point) Point {
+	var r = Identity
+
+	if k < 0 {
+		k = -k
+		p = c.Neg(p)
+	}
+
+	for ; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			r = c.Add(r, p)
+		}
+
+		p = c.Add(p, p)
+	}
+
+	return r
+}
+
+// montgomery returns the birationally equivalent Montgomery curve,
+// A = 2(a + d) / (a - d) and B = 4 / (a - d).
+func (c *Curve) montgomery() *ec.Montgomery {
+	var f = c.F
+	var inv, _ = f.Inverse(f.Add(c.A, f.Neg(c.D)))
+	var m, err = ec.NewMontgomery(f,
+		f.Multiply(2, f.Multiply(f.Add(c.A, c.D), inv)),
+		f.Multiply(4, inv),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// toMontgomery maps (x, y) to (u, v) = ((1 + y) / (1 - y), u / x).
+// The identity element maps to the identity element, and (0, -1) to
+// (0, 0) which is of order two.
+func (c *Curve) toMontgomery(p Point) (ec.Point, error) {
+	var f = c.F
+
+	if p.X == 0 {
+		if f.Canonicalize(p.Y) == 1 {
+			return ec.Point{Inf: true}, nil
+		}
+
+		return ec.Point{X: 0, Y: 0}, nil
+	}
+
+	var inv, err = f.Inverse(f.Add(1, f.Neg(p.Y)))
+	if err != nil {
+		return ec.Point{}, errors.New("point has no Montgomery image")
+	}
+
+	var u = f.Multiply(f.Add(1, p.Y), inv)
+	var xinv, _ = f.Inverse(p.X)
+
+	return ec.Point{X: u, Y: f.Multiply(u, xinv)}, nil
+}
+
+// fromMontgomery maps (u, v) to (x, y) = (u / v, (u - 1) / (u + 1)).
+func (c *Curve) fromMontgomery(p ec.Point) (Point, error) {
+	var f = c.F
+
+	if p.Inf {
+		return Identity, nil
+	}
+
+	if p.X == 0 && p.Y == 0 {
+		return Point{X: 0, Y: f.Neg(1)}, nil
+	}
+
+	var vinv, err1 = f.Inverse(p.Y)
+	var uinv, err2 = f.Inverse(f.Add(p.X, 1))
+	if err1 != nil || err2 != nil {
+		return Point{}, errors.New("point has no Edwards image")
+	}
+
+	return Point{
+		X: f.Multiply(p.X, vinv),
+		Y: f.Multiply(f.Add(p.X, f.Neg(1)), uinv),
+	}, nil
+}
+
+// Weierstrass returns a Weierstrass curve birationally equivalent to c,
+// via the equivalent Montgomery curve. The generator and its order are
+// mapped as well.
+func (c *Curve) Weierstrass() (*ec.Curve, error) {
+	var m = c.montgomery()
+	var g, err = c.toMontgomery(c.G)
+	if err != nil {
+		return nil, err
+	}
+
+	m.G = g
+	m.N = c.N
+
+	return m.Weierstrass()
+}
+
+// ToWeierstrass maps a point on c to the curve returned by Weierstrass.
+// The map is a group homomorphism. An error is returned for the
+// exceptional points which has no image, those only exist if the curve
+// is not complete.
+func (c *Curve) ToWeierstrass(p Point) (ec.Point, error) {
+	var m = c.montgomery()
+	var mp, err = c.toMontgomery(p)
+	if err != nil {
+		return mp, err
+	}
+
+	return m.ToWeierstrass(mp), nil
+}
+
+// FromWeierstrass maps a point on the curve returned by Weierstrass to
+// c.
+func (c *Curve) FromWeierstrass(p ec.Point) (Point, error) {
+	var m = c.montgomery()
+
+	return c.fromMontgomery(m.FromWeierstrass(p))
+}
+
+// FromCurve returns a twisted Edwards curve birationally equivalent to
+// the Weierstrass curve wc, and the map from wc to it. wc must have a
+// point of order two, see ec.NewMontgomeryFromCurve. The twisted
+// Edwards parameters are a = (A + 2) / B and d = (A - 2) / B.
+func FromCurve(wc *ec.Curve) (*Curve, func(ec.Point) (Point, error),
+	error) {
+	var m, toM, err = ec.NewMontgomeryFromCurve(wc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var f = wc.F
+	var binv, _ = f.Inverse(m.B)
+	var c *Curve
+
+	c, err = New(f,
+		f.Multiply(f.Add(m.A, 2), binv),
+		f.Multiply(f.Add(m.A, f.Neg(2)), binv),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// This is the inverse of toMontgomery, as
+	// A = 2(a + d) / (a - d) and B = 4 / (a - d).
+	var toE = func(p ec.Point) (Point, error) {
+		return c.fromMontgomery(toM(p))
+	}
+
+	c.N = wc.N
+	if c.G, err = toE(wc.G); err != nil {
+		return nil, nil, err
+	}
+	if c.N != 0 {
+		var n int64
+		if n, err = wc.CountPoints(); err != nil {
+			return nil, nil, err
+		}
+		c.H = n / c.N
+	}
+
+	return c, toE, nil
+}
+
+// CountPoints returns the number of points on the curve, computed on
+// the equivalent Weierstrass curve.
+// The exceptional points of an incomplete curve are included, as they
+// correspond to points at infinity on the projective closure.
+func (c *Curve) CountPoints() int64 {
+	var wc, err = c.montgomery().Weierstrass()
+	if err != nil {
+		panic(err)
+	}
+
+	var n int64
+	if n, err = wc.CountPoints(); err != nil {
+		panic(err)
+	}
+
+	return n
+}
+
+// Order returns the order of p, which must be on the curve. The order
+// is found by removing prime factors from the number of points, as
+// long as the result still maps p to the identity.
+func (c *Curve) Order(p Point) int64 {
+	var n = c.CountPoints()
+
+	if c.N != 0 && c.H != 0 {
+		n = c.N * c.H
+	}
+
+	for _, q := range smath.PrimeFactors(n) {
+		if c.ScalarM(n/q, p) == Identity {
+			n /= q
+		}
+	}
+
+	return n
+}
+
+// Verify verifies all the parameters of the curve.
+func (c *Curve) Verify() error {
+	if !c.F.Modulus().ProbablyPrime(256) {
+		return fmt.Errorf("field order %d is not prime", c.F.P())
+	}
+
+	if c.A == 0 || c.D == 0 || c.A == c.D {
+		return fmt.Errorf("invalid parameters a:%d d:%d", c.A, c.D)
+	}
+
+	if !c.Complete() {
+		return errors.New("addition law is not complete")
+	}
+
+	if !c.Valid(c.G) {
+		return errors.New("generator point is not on curve")
+	}
+
+	if c.G == Identity {
+		return errors.New("generator point is the identity element")
+	}
+
+	if !big.NewInt(c.N).ProbablyPrime(256) {
+		return fmt.Errorf("curve order %d is not prime", c.N)
+	}
+
+	if o := c.Order(c.G); o != c.N {
+		return fmt.Errorf("order %d of generator does not match %d", o, c.N)
+	}
+
+	if n := c.CountPoints(); n != c.N*c.H {
+		return fmt.Errorf("number of points %d is not %d * %d", n, c.N, c.H)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package edwards
+
+import (
+	"testing"
+
+	"github.com/kommendorkapten/sigsim/pkg/ec"
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	var f = field.NewFinite(13)
+	var tests = []struct {
+		name string
+		a    int64
+		d    int64
+		err  bool
+	}{
+		{name: "ok", a: -1, d: 2, err: false},
+		{name: "a is zero", a: 0, d: 2, err: true},
+		{name: "d is zero", a: 1, d: 13, err: true},
+		{name: "a equals d", a: 3, d: 16, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var _, err = New(f, tt.a, tt.d)
+
+			assert.Equal(t, tt.err, err != nil)
+		})
+	}
+}
+
+func TestDemoCurve(t *testing.T) {
+	var c = DemoCurve
+	var inv, _ = c.F.Inverse(121665)
+
+	assert.Equal(t, c.F.Neg(c.F.Multiply(121666, inv)), c.D)
+	assert.True(t, c.Complete())
+	assert.Nil(t, c.Verify())
+	assert.Equal(t, Identity, c.ScalarM(c.N, c.G))
+
+	// Equivalent to the Montgomery demo curve, with u -> -u
+	var m = ec.DemoMontgomery
+	var u, _ = c.toMontgomery(c.G)
+
+	assert.Equal(t, c.F.Neg(m.A), c.montgomery().A)
+	assert.Equal(t, int64(0), m.ScalarMX(c.N, c.F.Neg(u.X)))
+}
+
+func TestAdd(t *testing.T) {
+	var c = DemoCurve
+	var wc, err = c.Weierstrass()
+
+	assert.Nil(t, err)
+	assert.True(t, wc.Valid(wc.G))
+
+	// Identity and negation
+	assert.Equal(t, c.G, c.Add(c.G, Identity))
+	assert.Equal(t, Identity, c.Add(c.G, c.Neg(c.G)))
+
+	for i := 0; i < 20; i++ {
+		var wp = wc.RandomPoint()
+		var wq = wc.RandomPoint()
+		var p, errp = c.FromWeierstrass(wp)
+		var q, errq = c.FromWeierstrass(wq)
+
+		assert.Nil(t, errp)
+		assert.Nil(t, errq)
+		assert.True(t, c.Valid(p))
+
+		// Unified addition agrees with the Weierstrass group law,
+		// doubling included
+		var wr, _ = c.ToWeierstrass(c.Add(p, q))
+		assert.Equal(t, wc.Add(wp, wq), wr)
+		wr, _ = c.ToWeierstrass(c.Add(p, p))
+		assert.Equal(t, wc.Add(wp, wp), wr)
+		wr, _ = c.ToWeierstrass(c.ScalarM(-12345, p))
+		assert.Equal(t, wc.ScalarM(-12345, wp), wr)
+	}
+
+	// The points of small order
+	var o = c.Order(Point{X: 0, Y: c.F.Neg(1)})
+	assert.Equal(t, int64(2), o)
+}
+
+func TestFromCurve(t *testing.T) {
+	var wc, _ = ec.DemoMontgomery.Weierstrass()
+	var c, toE, err = FromCurve(wc)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(8), c.H)
+	assert.True(t, c.Valid(c.G))
+	assert.Equal(t, c.N, c.Order(c.G))
+
+	for i := 0; i < 10; i++ {
+		var p = wc.RandomPoint()
+		var q = wc.RandomPoint()
+		var ep, _ = toE(p)
+		var eq, _ = toE(q)
+		var epq, _ = toE(wc.Add(p, q))
+
+		assert.True(t, c.Valid(ep))
+		assert.Equal(t, epq, c.Add(ep, eq))
+	}
+
+	// Prime order curves have no twisted Edwards form
+	_, _, err = FromCurve(ec.DemoCurve25)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,319 @@
+// Package extfield implements extension fields F_p^k of the prime
+// fields, i.e F_p[x] / (m(x)) where m is an irreducible polynomial of
+// degree k. They are needed for pairings, as the pairing of two points
+// on a curve over F_p takes its values in F_p^k where k is the
+// embedding degree.
+package extfield
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	smath "github.com/kommendorkapten/sigsim/pkg/math"
+	"github.com/kommendorkapten/sigsim/pkg/poly"
+)
+
+// Element is an element of an extension field, a polynomial over F_p of
+// degree less than k.
+type Element = poly.Poly[int64]
+
+// Field is the extension field F_p^k = F_p[x] / (m(x)).
+// It implements field.Field, so algorithms written for the generic
+// field interface, e.g ec.GenericCurve, work over the extension field
+// too. For the integer conversions an element c0 + c1x + ... is
+// identified with the integer c0 + c1p + c2p^2 + ... in [0, p^k).
+type Field struct {
+	f  *field.Finite
+	m  poly.Poly[int64] // Irreducible modulus
+	k  int              // Degree of the extension
+	q  *big.Int         // Order of the field, p^k
+	xp Element          // x^p mod m, used for the Frobenius map
+	nr Element          // Quadratic non-residue, for Sqrt
+}
+
+var _ field.Field[Element] = &Field{}
+
+// New returns the extension field F_p[x] / (m(x)). m must be monic and
+// irreducible over F_p, otherwise an error is returned.
+func New(f *field.Finite, m poly.Poly[int64]) (*Field, error) {
+	var k = m.Degree()
+
+	if k < 1 || m.Lead() != 1 {
+		return nil, fmt.Errorf("polynomial %s is not monic", m)
+	}
+
+	if !Irreducible(f, m) {
+		return nil, fmt.Errorf("polynomial %s is not irreducible", m)
+	}
+
+	var e = Field{
+		f: f,
+		m: m,
+		k: k,
+		q: new(big.Int).Exp(f.Modulus(), big.NewInt(int64(k)), nil),
+	}
+
+	e.xp = poly.X[int64](f).PowMod(f.Modulus(), m)
+	if f.P() != 2 {
+		e.nr = e.nonResidue()
+	}
+
+	return &e, nil
+}
+
+// Irreducible returns true if m is irreducible over F_p, using Rabin's
+// test: a polynomial m of degree k is irreducible iff m divides
+// x^(p^k) - x, and gcd(m, x^(p^(k/q)) - x) = 1 for all primes q
+// dividing k.
+func Irreducible(f *field.Finite, m poly.Poly[int64]) bool {
+	var k = m.Degree()
+	var x = poly.X[int64](f)
+	var p = f.Modulus()
+	// x^(p^j) - x mod m
+	var xpj = func(j int64) Element {
+		var e = new(big.Int).Exp(p, big.NewInt(j), nil)
+
+		return x.PowMod(e, m).Sub(x).Mod(m)
+	}
+
+	if k < 1 {
+		return false
+	}
+
+	if !xpj(int64(k)).IsZero() {
+		return false
+	}
+
+	for _, q := range smath.PrimeFactors(int64(k)) {
+		if poly.GCD(m, xpj(int64(k)/q)).Degree() != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FindIrreducible returns a monic irreducible polynomial of degree k,
+// searching x^k + ax - b for small a and b. For k = 2 this gives
+// x^2 - n where n is the smallest quadratic non-residue.
+func FindIrreducible(f *field.Finite, k int) poly.Poly[int64] {
+	var c = make([]int64, k+1)
+
+	c[k] = 1
+	for a := int64(0); ; a++ {
+		for b := int64(1); b < f.P() && b <= 100; b++ {
+			c[0] = f.Neg(b)
+			if k > 1 {
+				c[1] = f.Canonicalize(a)
+			}
+
+			var m = poly.New(f, c...)
+			if Irreducible(f, m) {
+				return m
+			}
+		}
+	}
+}
+
+func (e *Field) String() string {
+	return fmt.Sprintf("F_%d^%d mod %s", e.f.P(), e.k, e.m)
+}
+
+// Base returns the prime field F_p.
+func (e *Field) Base() *field.Finite {
+	return e.f
+}
+
+// K returns the degree of the extension.
+func (e *Field) K() int {
+	return e.k
+}
+
+// Poly returns the irreducible modulus.
+func (e *Field) Poly() poly.Poly[int64] {
+	return e.m
+}
+
+// Modulus returns the order of the field, p^k.
+func (e *Field) Modulus() *big.Int {
+	return new(big.Int).Set(e.q)
+}
+
+// New returns the element c[0] + c[1]x + c[2]x^2 ...
+func (e *Field) New(c ...int64) Element {
+	return poly.New(e.f, c...).Mod(e.m)
+}
+
+// Element returns true if i is a canonical member of the field.
+func (e *Field) Element(i Element) bool {
+	return i.Field() != nil && i.Degree() < e.k
+}
+
+// Canonicalize returns the canonical representation of i, reduced
+// modulo m.
+func (e *Field) Canonicalize(i Element) Element {
+	if i.Field() == nil {
+		// The zero value
+		return poly.Zero[int64](e.f)
+	}
+
+	return i.Mod(e.m)
+}
+
+// Equal returns true if i and j represent the same element.
+func (e *Field) Equal(i, j Element) bool {
+	return e.Canonicalize(i).Equal(e.Canonicalize(j))
+}
+
+// FromInt64 returns the canonical element for i, which is in F_p.
+func (e *Field) FromInt64(i int64) Element {
+	return poly.New(e.f, i)
+}
+
+// Int returns the integer c0 + c1p + c2p^2 + ... for i.
+func (e *Field) Int(i Element) *big.Int {
+	var r = new(big.Int)
+	var p = e.f.Modulus()
+
+	for j := i.Degree(); j >= 0; j-- {
+		r.Mul(r, p)
+		r.Add(r, big.NewInt(i.Coeff(j)))
+	}
+
+	return r
+}
+
+// FromInt returns the element with the base p digits of z as
+// coefficients. z must not be negative.
+func (e *Field) FromInt(z *big.Int) Element {
+	var c []int64
+	var d, m = new(big.Int).Set(z), new(big.Int)
+	var p = e.f.Modulus()
+
+	for d.Sign() > 0 {
+		d.DivMod(d, p, m)
+		c = append(c, m.Int64())
+	}
+
+	return poly.New(e.f, c...)
+}
+
+// Add returns i + j.
+func (e *Field) Add(i, j Element) Element {
+	return e.Canonicalize(i).Add(e.Canonicalize(j))
+}
+
+// Neg returns -i.
+func (e *Field) Neg(i Element) Element {
+	return e.Canonicalize(i).Neg()
+}
+
+// Multiply returns i * j.
+func (e *Field) Multiply(i, j Element) Element {
+	return e.Canonicalize(i).MulMod(e.Canonicalize(j), e.m)
+}
+
+// Inverse returns the multiplicative inverse of i.
+func (e *Field) Inverse(i Element) (Element, error) {
+	i = e.Canonicalize(i)
+	if i.IsZero() {
+		return i, errors.New("0 is not invertible")
+	}
+
+	return i.InverseMod(e.m)
+}
+
+// Exponentiate returns i^j, where j is interpreted as an integer, see
+// Int.
+func (e *Field) Exponentiate(i, j Element) Element {
+	return e.Exp(i, e.Int(j))
+}
+
+// Exp returns i^n. Negative exponents are supported for non-zero i.
+func (e *Field) Exp(i Element, n *big.Int) Element {
+	i = e.Canonicalize(i)
+
+	if n.Sign() < 0 {
+		var err error
+
+		if i, err = e.Inverse(i); err != nil {
+			panic(err)
+		}
+		n = new(big.Int).Neg(n)
+	}
+
+	return i.PowMod(n, e.m)
+}
+
+// Frobenius returns i^(p^n), the n:th power of the Frobenius map. As
+// c^p = c for c in F_p, i(x)^p = i(x^p), so the map is computed by
+// evaluating i at x^p, n times.
+func (e *Field) Frobenius(i Element, n int) Element {
+	i = e.Canonicalize(i)
+	n %= e.k
+	if n < 0 {
+		n += e.k
+	}
+
+	for ; n > 0; n-- {
+		// Horner's method with x^p
+		var r = poly.Zero[int64](e.f)
+
+		for j := i.Degree(); j >= 0; j-- {
+			r = r.MulMod(e.xp, e.m).Add(e.FromInt64(i.Coeff(j)))
+		}
+		i = r
+	}
+
+	return i
+}
+
+// Legendre returns 0 if i is zero, 1 if i is a square and -1 otherwise,
+// computed with Euler's criterion i^((q-1)/2).
+func (e *Field) Legendre(i Element) int {
+	i = e.Canonicalize(i)
+	if i.IsZero() {
+		return 0
+	}
+
+	var n = new(big.Int).Sub(e.q, big.NewInt(1))
+
+	n.Rsh(n, 1)
+	if e.Exp(i, n).Equal(e.FromInt64(1)) {
+		return 1
+	}
+
+	return -1
+}
+
+// Sqrt returns a square root of i, using Tonelli-Shanks.
+// The sqrt are +/- the returned value.
+// If i is not a square, an error is returned.
+func (e *Field) Sqrt(i Element) (Element, error) {
+	if e.f.P() == 2 {
+		// Squaring is a bijection, the root is i^(q/2)
+		return e.Exp(i, new(big.Int).Rsh(e.q, 1)), nil
+	}
+
+	return field.TonelliShanksWith[Element](e, i, e.nr)
+}
+
+// nonResidue returns a quadratic non-residue. If k is even all of F_p
+// are squares, so x + c is tried for c = 0, 1, ...
+func (e *Field) nonResidue() Element {
+	var c = e.FromInt64(0)
+
+	for {
+		var z = c
+		if e.k > 1 {
+			z = c.Add(poly.X[int64](e.f)).Mod(e.m)
+		}
+
+		if e.Legendre(z) == -1 {
+			return z
+		}
+		c = c.Add(e.FromInt64(1))
+	}
+}
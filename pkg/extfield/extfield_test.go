@@ -0,0 +1,117 @@
+package extfield
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	"github.com/kommendorkapten/sigsim/pkg/poly"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	var f = field.NewFinite(11)
+	var tests = []struct {
+		name string
+		c    []int64
+		err  bool
+	}{
+		{name: "x^2 + 1", c: []int64{1, 0, 1}, err: false},
+		{name: "x^2 - 1", c: []int64{-1, 0, 1}, err: true},
+		{name: "x^3 + x + 4", c: []int64{4, 1, 0, 1}, err: false},
+		{name: "x^4 + 1", c: []int64{1, 0, 0, 0, 1}, err: true},
+		{name: "not monic", c: []int64{1, 0, 2}, err: true},
+		{name: "constant", c: []int64{1}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var _, err = New(f, poly.New(f, tt.c...))
+
+			assert.Equal(t, tt.err, err != nil)
+		})
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	// F_p^2 = F_p(i), i^2 = -1, as p = 3 mod 4
+	var f = field.NewFinite(10007)
+	var e, err = New(f, poly.New(f, 1, 0, 1))
+	assert.Nil(t, err)
+
+	var a = e.New(3, 4)
+	var b = e.New(5, -2)
+
+	// (3 + 4i)(5 - 2i) = 15 - 6i + 20i + 8 = 23 + 14i
+	assert.True(t, e.Equal(e.New(23, 14), e.Multiply(a, b)))
+	assert.True(t, e.Equal(e.New(8, 2), e.Add(a, b)))
+
+	var inv, _ = e.Inverse(a)
+	assert.True(t, e.Equal(e.FromInt64(1), e.Multiply(a, inv)))
+
+	_, err = e.Inverse(e.FromInt64(0))
+	assert.NotNil(t, err)
+
+	// The Frobenius map is complex conjugation
+	assert.True(t, e.Equal(e.New(3, -4), e.Frobenius(a, 1)))
+	assert.True(t, e.Equal(e.Exp(a, f.Modulus()), e.Frobenius(a, 1)))
+
+	// The multiplicative group has order p^2 - 1
+	var q1 = e.Modulus()
+	q1.Sub(q1, big.NewInt(1))
+	assert.True(t, e.Equal(e.FromInt64(1), e.Exp(a, q1)))
+	assert.True(t, e.Equal(inv, e.Exp(a, big.NewInt(-1))))
+
+	// Integer representation
+	var n = e.Int(a)
+	assert.Equal(t, int64(3+4*10007), n.Int64())
+	assert.True(t, e.Equal(a, e.FromInt(n)))
+	assert.True(t, e.Equal(e.Exp(a, n), e.Exponentiate(a, a)))
+}
+
+func TestFrobenius(t *testing.T) {
+	var f = field.NewFinite(103)
+
+	for _, k := range []int{2, 3, 6, 12} {
+		var e, err = New(f, FindIrreducible(f, k))
+		assert.Nil(t, err)
+
+		var a = e.New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+		var b = e.New(-7, 0, 42, 1)
+
+		for n := 0; n <= k; n++ {
+			var pn = new(big.Int).Exp(f.Modulus(), big.NewInt(int64(n)),
+				nil)
+
+			assert.True(t, e.Equal(e.Exp(a, pn), e.Frobenius(a, n)))
+			// The Frobenius map is a field automorphism
+			assert.True(t, e.Equal(e.Multiply(e.Frobenius(a, n),
+				e.Frobenius(b, n)), e.Frobenius(e.Multiply(a, b), n)))
+		}
+
+		assert.True(t, e.Equal(a, e.Frobenius(a, k)))
+		assert.True(t, e.Equal(e.Frobenius(a, k-1), e.Frobenius(a, -1)))
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	var f = field.NewFinite(97)
+
+	for _, k := range []int{1, 2, 3} {
+		var e, _ = New(f, FindIrreducible(f, k))
+
+		for i := int64(1); i < 40; i++ {
+			var a = e.New(i, 2*i+1, i*i)
+			var r, err = e.Sqrt(e.Multiply(a, a))
+
+			assert.Nil(t, err)
+			assert.True(t, e.Equal(e.Multiply(a, a), e.Multiply(r, r)))
+			assert.Equal(t, 1, e.Legendre(e.Multiply(a, a)))
+		}
+
+		// All elements of F_p are squares in extensions of even degree
+		assert.Equal(t, k%2 == 0, e.Legendre(e.FromInt64(5)) == 1)
+		var _, err = e.Sqrt(e.nr)
+		assert.NotNil(t, err)
+	}
+}
@@ -0,0 +1,179 @@
+package field
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// BigFinite defines a finite prime field of arbitrary size.
+// Elements are represented as big.Int. No operation modifies its
+// arguments, a newly allocated value is always returned.
+type BigFinite struct {
+	p *big.Int // Order of the finite field
+}
+
+var _ Field[*big.Int] = &BigFinite{}
+
+// NewBigFinite initializes and returns a finite field of order p.
+func NewBigFinite(p *big.Int) *BigFinite {
+	return &BigFinite{
+		p: new(big.Int).Set(p),
+	}
+}
+
+// P returns the order of the finite field.
+func (f *BigFinite) P() *big.Int {
+	return new(big.Int).Set(f.p)
+}
+
+// Modulus returns the order of the finite field.
+func (f *BigFinite) Modulus() *big.Int {
+	return f.P()
+}
+
+// Element returns true if the provided element is a member of the field.
+func (f *BigFinite) Element(i *big.Int) bool {
+	if i.Sign() < 0 {
+		return false
+	}
+
+	return i.Cmp(f.p) < 0
+}
+
+// Canonicalize returns the canonical representation of an element.
+// The canonical representation is always positive and mod P.
+func (f *BigFinite) Canonicalize(i *big.Int) *big.Int {
+	// Mod implements Euclidean modulus, the result is never negative.
+	return new(big.Int).Mod(i, f.p)
+}
+
+// Equal returns true if i and j are congruent mod P.
+func (f *BigFinite) Equal(i, j *big.Int) bool {
+	if f.canonical(i) && f.canonical(j) {
+		return i.Cmp(j) == 0
+	}
+
+	return f.Canonicalize(i).Cmp(f.Canonicalize(j)) == 0
+}
+
+// FromInt64 returns the canonical element for i.
+func (f *BigFinite) FromInt64(i int64) *big.Int {
+	return f.Canonicalize(big.NewInt(i))
+}
+
+// Int returns a copy of i.
+func (f *BigFinite) Int(i *big.Int) *big.Int {
+	return new(big.Int).Set(i)
+}
+
+// FromInt returns a copy of z.
+func (f *BigFinite) FromInt(z *big.Int) *big.Int {
+	return new(big.Int).Set(z)
+}
+
+// canonical returns true if i is in [0, P).
+func (f *BigFinite) canonical(i *big.Int) bool {
+	return i.Sign() >= 0 && i.Cmp(f.p) < 0
+}
+
+// Neg returns the additive inverse of i.
+func (f *BigFinite) Neg(i *big.Int) *big.Int {
+	// Avoid the division for the common case
+	if f.canonical(i) {
+		if i.Sign() == 0 {
+			return new(big.Int)
+		}
+
+		return new(big.Int).Sub(f.p, i)
+	}
+
+	var z = new(big.Int).Neg(i)
+
+	return z.Mod(z, f.p)
+}
+
+// Add two elements and return the canonicalized result.
+func (f *BigFinite) Add(i, j *big.Int) *big.Int {
+	var z = new(big.Int).Add(i, j)
+
+	// Avoid the division for the common case
+	if f.canonical(i) && f.canonical(j) {
+		if z.Cmp(f.p) >= 0 {
+			z.Sub(z, f.p)
+		}
+
+		return z
+	}
+
+	return z.Mod(z, f.p)
+}
+
+// Multiply two elements and return the result.
+func (f *BigFinite) Multiply(i, j *big.Int) *big.Int {
+	var z = new(big.Int).Mul(i, j)
+
+	return z.Mod(z, f.p)
+}
+
+// Inverse computes the multiplicative inverse of i mod P.
+func (f *BigFinite) Inverse(i *big.Int) (*big.Int, error) {
+	var z = new(big.Int)
+
+	if z.ModInverse(f.Canonicalize(i), f.p) == nil {
+		return nil, fmt.Errorf("%s is not invertible", i)
+	}
+
+	return z, nil
+}
+
+// Exponentiate raises i to the power of j mod P.
+func (f *BigFinite) Exponentiate(i, j *big.Int) *big.Int {
+	return new(big.Int).Exp(f.Canonicalize(i), j, f.p)
+}
+
+// Legendre returns the Legendre symbol of i, that is 0 if i is
+// congruent 0, 1 if i is a quadratic residue and -1 if i is not a
+// quadratic residue mod P.
+func (f *BigFinite) Legendre(i *big.Int) int {
+	var c = f.Canonicalize(i)
+
+	// Every element of F_2 is a square, and Jacobi needs an odd P
+	if f.p.Cmp(big.NewInt(2)) == 0 {
+		return int(c.Int64())
+	}
+
+	return big.Jacobi(c, f.p)
+}
+
+// Sqrt computes the square root of i in this field.
+// If P is congruent 3 mod 4 a direct formula is used (see Finite.Sqrt
+// for details), otherwise the Tonelli-Shanks algorithm.
+// The sqrt are +/- the returned value.
+// If i is not square mod P, an error is returned.
+func (f *BigFinite) Sqrt(i *big.Int) (*big.Int, error) {
+	var rem = new(big.Int).Mod(f.p, four)
+
+	if !f.Element(i) {
+		panic(i)
+	}
+
+	if rem.Cmp(three) != 0 {
+		return TonelliShanks[*big.Int](f, i)
+	}
+
+	if i.Sign() == 0 {
+		return new(big.Int), nil
+	}
+
+	// e = (p + 1) / 4
+	var e = new(big.Int).Add(f.p, one)
+	e.Rsh(e, 2)
+
+	var x = f.Exponentiate(i, e)
+
+	if f.Multiply(x, x).Cmp(i) == 0 {
+		return x, nil
+	}
+
+	return nil, fmt.Errorf("%s is not a square mod %s", i, f.p)
+}
@@ -0,0 +1,94 @@
+package field
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBigMatchesFinite(t *testing.T) {
+	var tests = []int64{11, 89, 101, 2003, 996488947583}
+
+	for _, p := range tests {
+		var f = NewFinite(p)
+		var bf = NewBigFinite(big.NewInt(p))
+		var values = []int64{-1, 0, 1, 2, p / 3, p / 2, p - 1, p, p + 5}
+
+		for _, i := range values {
+			var bi = big.NewInt(i)
+
+			assert.Equal(t, f.Canonicalize(i),
+				bf.Canonicalize(bi).Int64(), "Canonicalize %d", i)
+			assert.Equal(t, f.Neg(i), bf.Neg(bi).Int64(), "Neg %d", i)
+
+			for _, j := range values {
+				var bj = big.NewInt(j)
+
+				assert.Equal(t, f.Add(f.Canonicalize(i), j),
+					bf.Add(bf.Canonicalize(bi), bj).Int64(),
+					"Add %d %d", i, j)
+				assert.Equal(t, f.Multiply(f.Canonicalize(i), j),
+					bf.Multiply(bf.Canonicalize(bi), bj).Int64(),
+					"Multiply %d %d", i, j)
+			}
+		}
+	}
+}
+
+func TestBigInverse(t *testing.T) {
+	var f = NewBigFinite(big.NewInt(101))
+	var r, err = f.Inverse(big.NewInt(13))
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(70), r.Int64())
+
+	r, err = f.Inverse(big.NewInt(-1))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), r.Int64())
+
+	_, err = f.Inverse(big.NewInt(0))
+	assert.NotNil(t, err)
+
+	_, err = NewBigFinite(big.NewInt(2773)).Inverse(big.NewInt(1410))
+	assert.NotNil(t, err)
+}
+
+func TestBigExponentiate(t *testing.T) {
+	var f = NewBigFinite(big.NewInt(179))
+	var r = f.Exponentiate(big.NewInt(2), big.NewInt(4))
+
+	assert.Equal(t, int64(16), r.Int64())
+}
+
+func TestBigSqrt(t *testing.T) {
+	// p = 2^127 - 1 is a Mersenne prime congruent 3 mod 4.
+	var p = new(big.Int).Lsh(big.NewInt(1), 127)
+	p.Sub(p, one)
+
+	var f = NewBigFinite(p)
+	var x = big.NewInt(1234567891011)
+	var sq = f.Multiply(x, x)
+	var r, err = f.Sqrt(sq)
+
+	assert.Nil(t, err)
+	assert.True(t, r.Cmp(x) == 0 || f.Neg(r).Cmp(x) == 0)
+
+	// -1 is never a square when p is congruent 3 mod 4
+	_, err = f.Sqrt(f.Neg(one))
+	assert.NotNil(t, err)
+}
+
+func TestBigDoesNotModifyArguments(t *testing.T) {
+	var f = NewBigFinite(big.NewInt(97))
+	var i = big.NewInt(96)
+	var j = big.NewInt(200)
+
+	f.Add(i, j)
+	f.Multiply(i, j)
+	f.Neg(i)
+	f.Canonicalize(j)
+
+	assert.Equal(t, int64(96), i.Int64())
+	assert.Equal(t, int64(200), j.Int64())
+}
@@ -0,0 +1,256 @@
+package field
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	smath "github.com/kommendorkapten/sigsim/pkg/math"
+)
+
+// Binary defines a binary extension field GF(2^m), using a polynomial
+// basis. An element is a polynomial over GF(2) of degree less than m,
+// stored as the bits of an uint64 with bit i being the coefficient of
+// x^i. Arithmetic is done modulo an irreducible polynomial of degree m.
+// Addition is xor, and every element is its own additive inverse, so
+// the field has characteristic two. Unlike the prime fields, every
+// element has a unique square root.
+type Binary struct {
+	m    int    // Degree of the extension
+	poly uint64 // Irreducible polynomial, including the x^m term
+}
+
+// NewBinary initializes and returns the field GF(2^m), with the
+// reduction polynomial poly, e.g 1<<41 | 1<<3 | 1 for x^41 + x^3 + 1.
+// m must be in [1, 63] and poly must be an irreducible polynomial of
+// degree m, otherwise an error is returned.
+func NewBinary(m int, poly uint64) (*Binary, error) {
+	if m < 1 || m > 63 {
+		return nil, fmt.Errorf("degree %d is not in [1, 63]", m)
+	}
+
+	if bits.Len64(poly)-1 != m {
+		return nil, fmt.Errorf("polynomial %#x is not of degree %d",
+			poly, m)
+	}
+
+	var f = Binary{m: m, poly: poly}
+
+	if !f.irreducible() {
+		return nil, fmt.Errorf("polynomial %#x is not irreducible", poly)
+	}
+
+	return &f, nil
+}
+
+// irreducible performs Rabin's test: a polynomial f of degree m is
+// irreducible iff f divides x^(2^m) - x, and
+// gcd(f, x^(2^(m/q)) - x) = 1 for all primes q dividing m.
+func (f *Binary) irreducible() bool {
+	// x^(2^k) mod f for k = 0..m
+	var xk = make([]uint64, f.m+1)
+
+	xk[0] = f.reduce(2)
+	for k := 1; k <= f.m; k++ {
+		xk[k] = f.Square(xk[k-1])
+	}
+
+	if xk[f.m] != xk[0] {
+		return false
+	}
+
+	for _, q := range smath.PrimeFactors(int64(f.m)) {
+		if gcd2(f.poly, xk[f.m/int(q)]^xk[0]) != 1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// gcd2 returns the greatest common divisor of two polynomials over
+// GF(2).
+func gcd2(a, b uint64) uint64 {
+	for b != 0 {
+		for bits.Len64(a) >= bits.Len64(b) {
+			a ^= b << (bits.Len64(a) - bits.Len64(b))
+		}
+		a, b = b, a
+	}
+
+	return a
+}
+
+// reduce returns i mod the field polynomial, for any i.
+func (f *Binary) reduce(i uint64) uint64 {
+	for bits.Len64(i) > f.m {
+		i ^= f.poly << (bits.Len64(i) - 1 - f.m)
+	}
+
+	return i
+}
+
+// M returns the degree of the extension, the field has 2^m elements.
+func (f *Binary) M() int {
+	return f.m
+}
+
+// Poly returns the reduction polynomial.
+func (f *Binary) Poly() uint64 {
+	return f.poly
+}
+
+func (f *Binary) String() string {
+	return fmt.Sprintf("GF(2^%d) mod %#x", f.m, f.poly)
+}
+
+// Element returns true if the provided element is a member of the field.
+func (f *Binary) Element(i uint64) bool {
+	return bits.Len64(i) <= f.m
+}
+
+// Add returns i + j, which is the same as i - j.
+func (f *Binary) Add(i, j uint64) uint64 {
+	return i ^ j
+}
+
+// Multiply two elements and return the result.
+// Computed with shift-and-add, reducing after every shift so the
+// intermediate values never exceed m bits.
+func (f *Binary) Multiply(i, j uint64) uint64 {
+	var r uint64
+	var top = uint64(1) << f.m
+
+	for ; j != 0; j >>= 1 {
+		if j&1 == 1 {
+			r ^= i
+		}
+
+		i <<= 1
+		if i&top != 0 {
+			i ^= f.poly
+		}
+	}
+
+	return r
+}
+
+// Square returns i^2. Squaring is a linear map in characteristic two,
+// (a + b)^2 = a^2 + b^2.
+func (f *Binary) Square(i uint64) uint64 {
+	return f.Multiply(i, i)
+}
+
+// Exponentiate raises i to the power of j.
+func (f *Binary) Exponentiate(i, j uint64) uint64 {
+	var r uint64 = 1
+
+	for ; j != 0; j >>= 1 {
+		if j&1 == 1 {
+			r = f.Multiply(r, i)
+		}
+
+		i = f.Square(i)
+	}
+
+	return r
+}
+
+// Inverse computes the multiplicative inverse of i, using the extended
+// Euclidean algorithm for polynomials over GF(2).
+func (f *Binary) Inverse(i uint64) (uint64, error) {
+	// Any multiple of the field polynomial is zero in the field
+	var u, v = f.reduce(i), f.poly
+	if u == 0 {
+		return 0, errors.New("0 is not invertible")
+	}
+
+	// Invariant: u = g1 * i and v = g2 * i mod poly
+	var g1, g2 = uint64(1), uint64(0)
+
+	for u != 1 {
+		var j = bits.Len64(u) - bits.Len64(v)
+
+		if j < 0 {
+			u, v = v, u
+			g1, g2 = g2, g1
+			j = -j
+		}
+
+		u ^= v << j
+		g1 ^= g2 << j
+	}
+
+	return f.reduce(g1), nil
+}
+
+// Sqrt returns the unique square root of i, i^(2^(m-1)).
+func (f *Binary) Sqrt(i uint64) uint64 {
+	return f.Frobenius(i, f.m-1)
+}
+
+// Trace returns the absolute trace of i, i + i^2 + i^4 + ... +
+// i^(2^(m-1)), which is either 0 or 1.
+func (f *Binary) Trace(i uint64) uint64 {
+	var t = i
+
+	for k := 1; k < f.m; k++ {
+		i = f.Square(i)
+		t ^= i
+	}
+
+	return t
+}
+
+// SolveQuadratic returns a solution z to z^2 + z = c, the other
+// solution is z + 1. A solution exists iff the trace of c is zero,
+// otherwise an error is returned.
+// For odd m the half-trace is a solution. For even m an element d of
+// trace one is used, and the solution is
+// z = sum_{i=0}^{m-2} (sum_{j=i+1}^{m-1} d^(2^j)) c^(2^i).
+// See IEEE 1363-2000, A.4.
+func (f *Binary) SolveQuadratic(c uint64) (uint64, error) {
+	if f.Trace(c) != 0 {
+		return 0, fmt.Errorf("%#x has trace one", c)
+	}
+
+	if f.m%2 == 1 {
+		// Half-trace, sum_{i=0}^{(m-1)/2} c^(2^(2i))
+		var z = c
+
+		for k := 1; k <= (f.m-1)/2; k++ {
+			c = f.Square(f.Square(c))
+			z ^= c
+		}
+
+		return z, nil
+	}
+
+	var d uint64
+
+	for k := 0; k < f.m; k++ {
+		if d = 1 << k; f.Trace(d) == 1 {
+			break
+		}
+	}
+
+	var z, w uint64
+
+	// w = sum_{j=i+1}^{m-1} d^(2^j), computed from the top
+	for i := f.m - 2; i >= 0; i-- {
+		w ^= f.Frobenius(d, i+1)
+		z ^= f.Multiply(w, f.Frobenius(c, i))
+	}
+
+	return z, nil
+}
+
+// Frobenius returns i^(2^k), i.e the Frobenius map x -> x^2 applied k
+// times.
+func (f *Binary) Frobenius(i uint64, k int) uint64 {
+	for ; k > 0; k-- {
+		i = f.Square(i)
+	}
+
+	return i
+}
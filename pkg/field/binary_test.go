@@ -0,0 +1,85 @@
+package field
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBinary(t *testing.T) {
+	var tests = []struct {
+		name string
+		m    int
+		poly uint64
+		err  bool
+	}{
+		{name: "GF(2^8) AES", m: 8, poly: 0x11b, err: false},
+		{name: "GF(2^41)", m: 41, poly: 1<<41 | 1<<3 | 1, err: false},
+		{name: "GF(2^63)", m: 63, poly: 1<<63 | 1<<1 | 1, err: false},
+		{name: "reducible", m: 4, poly: 0b10101, err: true},
+		{name: "has root one", m: 3, poly: 0b1111, err: true},
+		{name: "wrong degree", m: 5, poly: 0x11b, err: true},
+		{name: "too large", m: 64, poly: 1<<63 | 1, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var _, err = NewBinary(tt.m, tt.poly)
+
+			assert.Equal(t, tt.err, err != nil)
+		})
+	}
+}
+
+func TestBinaryArithmetic(t *testing.T) {
+	// The AES field, see FIPS 197 section 4.2
+	var f, _ = NewBinary(8, 0x11b)
+
+	assert.Equal(t, uint64(0xd4), f.Add(0x57, 0x83))
+	assert.Equal(t, uint64(0xc1), f.Multiply(0x57, 0x83))
+	assert.Equal(t, uint64(0xfe), f.Multiply(0x57, 0x13))
+
+	var i, err = f.Inverse(0x53)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0xca), i)
+
+	_, err = f.Inverse(0)
+	assert.NotNil(t, err)
+	_, err = f.Inverse(0x11b)
+	assert.NotNil(t, err)
+
+	var x uint64
+	for x = 1; x < 256; x++ {
+		var inv, _ = f.Inverse(x)
+
+		assert.Equal(t, uint64(1), f.Multiply(x, inv))
+		assert.Equal(t, x, f.Square(f.Sqrt(x)))
+		// The multiplicative group has order 255
+		assert.Equal(t, uint64(1), f.Exponentiate(x, 255))
+	}
+}
+
+func TestBinarySolveQuadratic(t *testing.T) {
+	// Both odd and even degrees
+	for _, m := range []int{7, 8} {
+		var f, _ = NewBinary(m, map[int]uint64{7: 0x83, 8: 0x11b}[m])
+		var solved = 0
+		var c uint64
+
+		for c = 0; f.Element(c); c++ {
+			var z, err = f.SolveQuadratic(c)
+
+			if f.Trace(c) == 1 {
+				assert.NotNil(t, err)
+				continue
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, c, f.Add(f.Square(z), z))
+			solved++
+		}
+
+		// Half of the elements have trace zero
+		assert.Equal(t, 1<<(m-1), solved)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"math/big"
 	"math/bits"
+
+	smath "github.com/kommendorkapten/sigsim/pkg/math"
 )
 
 // BitLength is the max bitsize for the integer used to define the
@@ -14,10 +16,49 @@ import (
 const BitLength = 64
 
 var (
-	zero = big.NewInt(0)
-	one  = big.NewInt(1)
+	zero  = big.NewInt(0)
+	one   = big.NewInt(1)
+	three = big.NewInt(3)
+	four  = big.NewInt(4)
 )
 
+// Field is the set of operations shared by the finite field backends.
+// It allows algorithms to be written once and run over either the int64
+// backed Finite or the big.Int backed BigFinite.
+type Field[E any] interface {
+	// Element returns true if i is a member of the field.
+	Element(i E) bool
+	// Canonicalize returns the canonical representation of i.
+	Canonicalize(i E) E
+	// Add returns i + j.
+	Add(i, j E) E
+	// Neg returns the additive inverse of i.
+	Neg(i E) E
+	// Multiply returns i * j.
+	Multiply(i, j E) E
+	// Inverse returns the multiplicative inverse of i.
+	Inverse(i E) (E, error)
+	// Exponentiate returns i raised to the power of j.
+	Exponentiate(i, j E) E
+	// Sqrt returns a square root of i.
+	Sqrt(i E) (E, error)
+	// Legendre returns the Legendre symbol of i.
+	Legendre(i E) int
+	// Equal returns true if i and j represent the same element.
+	Equal(i, j E) bool
+	// FromInt64 returns the canonical element for i.
+	FromInt64(i int64) E
+	// Int returns the integer value of i, no reduction is performed.
+	Int(i E) *big.Int
+	// FromInt returns the representation of z, no reduction is
+	// performed.
+	FromInt(z *big.Int) E
+	// Modulus returns the order of the field.
+	Modulus() *big.Int
+}
+
+var _ Field[int64] = &Finite{}
+
 // Finite defines a finite prime field.
 // For simplicity it's using a 64bit integer.
 // Internal calculations are done using big.Int to avoid any overflows.
@@ -39,6 +80,11 @@ func (f *Finite) P() int64 {
 	return f.p
 }
 
+// Modulus returns the order of the field as a big.Int.
+func (f Finite) Modulus() *big.Int {
+	return big.NewInt(f.p)
+}
+
 // Element returns true if the provided element is a member of the field.
 func (f Finite) Element(i int64) bool {
 	if i < 0 {
@@ -77,6 +123,36 @@ func (f Finite) canonicalize(z *big.Int) int64 {
 	return z.Int64()
 }
 
+// Equal returns true if i and j are congruent mod P.
+func (f Finite) Equal(i, j int64) bool {
+	return f.Canonicalize(i) == f.Canonicalize(j)
+}
+
+// FromInt64 returns the canonical element for i.
+func (f Finite) FromInt64(i int64) int64 {
+	return f.Canonicalize(i)
+}
+
+// Int returns i as a big.Int.
+func (f Finite) Int(i int64) *big.Int {
+	return big.NewInt(i)
+}
+
+// FromInt returns z as an int64. z must fit in 64 bits.
+func (f Finite) FromInt(z *big.Int) int64 {
+	return z.Int64()
+}
+
+// Neg returns the additive inverse of i.
+func (f Finite) Neg(i int64) int64 {
+	i = f.Canonicalize(i)
+	if i == 0 {
+		return 0
+	}
+
+	return f.p - i
+}
+
 // Add to elements and return the canonicalize result.
 func (f Finite) Add(i, j int64) int64 {
 	if i < 0 {
@@ -188,11 +264,23 @@ func (f Finite) Exponentiate(i, j int64) int64 {
 	return f.Canonicalize(r)
 }
 
+// Legendre returns the Legendre symbol of i, that is 0 if i is
+// congruent 0, 1 if i is a quadratic residue and -1 if i is not a
+// quadratic residue mod N.
+func (f Finite) Legendre(i int64) int {
+	// Every element of F_2 is a square, and Jacobi needs an odd N
+	if f.p == 2 {
+		return int(f.Canonicalize(i))
+	}
+
+	return smath.Jacobi(i, f.p)
+}
+
 // Sqrt computes the square root of i in this field.
-// This operation only works if N is congruent 3 mod 4.
+// If N is congruent 3 mod 4 a direct formula is used, otherwise
+// the Tonelli-Shanks algorithm.
 // The sqrt are +/- the returned value.
-// If the field is over a number not congruent 3 mod 4, or i is not square
-// mod N, an error is returned.
+// If i is not square mod N, an error is returned.
 func (f Finite) Sqrt(i int64) (int64, error) {
 	var rem = f.p % 4
 
@@ -201,7 +289,7 @@ func (f Finite) Sqrt(i int64) (int64, error) {
 	}
 
 	if rem != 3 {
-		return 0, fmt.Errorf("Sqrt not implemented for this field n: %d", f.p)
+		return TonelliShanks[int64](f, i)
 	}
 
 	if i == 0 {
@@ -227,3 +227,84 @@ func TestSqrt(t *testing.T) {
 		}
 	}
 }
+
+func TestSqrtAnyPrime(t *testing.T) {
+	// Primes congruent 1 mod 4, 998244353 - 1 is divisible by 2^23
+	var primes = []int64{5, 13, 17, 41, 97, 33489593, 998244353}
+
+	for _, p := range primes {
+		var f = NewFinite(p)
+		var bf = NewBigFinite(big.NewInt(p))
+		var values = []int64{0, 1, 2, 3, 4, p / 3, p / 2, p - 1}
+
+		for _, x := range values {
+			var sq = f.Multiply(x, x)
+			var roots = []int64{x, f.Neg(x)}
+			var r, err = f.Sqrt(sq)
+
+			assert.Nil(t, err)
+			assert.Contains(t, roots, r, "Sqrt(%d) mod %d", sq, p)
+
+			r, err = Cipolla[int64](f, sq)
+			assert.Nil(t, err)
+			assert.Contains(t, roots, r, "Cipolla(%d) mod %d", sq, p)
+
+			var br *big.Int
+			br, err = bf.Sqrt(big.NewInt(sq))
+			assert.Nil(t, err)
+			assert.Contains(t, roots, br.Int64(),
+				"big Sqrt(%d) mod %d", sq, p)
+		}
+	}
+
+	// In F_2 every element is its own square root
+	var f2 = NewFinite(2)
+	var bf2 = NewBigFinite(big.NewInt(2))
+	for _, i := range []int64{0, 1} {
+		var r, err = f2.Sqrt(i)
+		assert.Nil(t, err)
+		assert.Equal(t, i, r)
+
+		r, err = Cipolla[int64](f2, i)
+		assert.Nil(t, err)
+		assert.Equal(t, i, r)
+
+		var br *big.Int
+		br, err = bf2.Sqrt(big.NewInt(i))
+		assert.Nil(t, err)
+		assert.Equal(t, i, br.Int64())
+	}
+
+	// Quadratic non-residues mod 13
+	var f = NewFinite(13)
+	for _, i := range []int64{2, 5, 6, 7, 8, 11} {
+		var _, err = f.Sqrt(i)
+		assert.NotNil(t, err, "%d is not a square mod 13", i)
+
+		_, err = Cipolla[int64](f, i)
+		assert.NotNil(t, err, "%d is not a square mod 13", i)
+	}
+}
+
+func TestLegendre(t *testing.T) {
+	for _, p := range []int64{2, 3, 5, 11, 13, 101, 2003} {
+		var f = NewFinite(p)
+		var bf = NewBigFinite(big.NewInt(p))
+
+		for i := int64(0); i < p; i++ {
+			// Euler's criterion
+			var exp = 0
+			switch e := f.Exponentiate(i, (p-1)/2); {
+			case i == 0:
+			case e == 1:
+				exp = 1
+			case e == p-1:
+				exp = -1
+			}
+
+			assert.Equal(t, exp, f.Legendre(i), "(%d/%d)", i, p)
+			assert.Equal(t, exp, bf.Legendre(big.NewInt(i)),
+				"(%d/%d)", i, p)
+		}
+	}
+}
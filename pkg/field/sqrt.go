@@ -0,0 +1,178 @@
+package field
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// isF2 returns true if f is the field with two elements, where there
+// are no quadratic non-residues.
+func isF2[E any](f Field[E]) bool {
+	return f.Modulus().Cmp(big.NewInt(2)) == 0
+}
+
+// TonelliShanks computes a square root of i in any prime field.
+// The sqrt are +/- the returned value.
+// If i is not a square, an error is returned.
+// nolint: lll
+// nolint: revive
+// See https://en.wikipedia.org/wiki/Tonelli%E2%80%93Shanks_algorithm
+// for reference.
+func TonelliShanks[E any](f Field[E], i E) (E, error) {
+	var zeroE = f.FromInt64(0)
+	var oneE = f.FromInt64(1)
+
+	i = f.Canonicalize(i)
+	if f.Equal(i, zeroE) || isF2(f) {
+		// In F_2 every element is its own square root
+		return i, nil
+	}
+
+	if f.Legendre(i) != 1 {
+		return zeroE, fmt.Errorf("%v is not a square mod %s",
+			i, f.Modulus())
+	}
+
+	// Find a quadratic non-residue z by trial. Half of all elements
+	// are non-residues so this terminates quickly.
+	var z = f.FromInt64(2)
+	for f.Legendre(z) != -1 {
+		z = f.Add(z, oneE)
+	}
+
+	return tonelliShanks(f, i, z), nil
+}
+
+// TonelliShanksWith computes a square root of i like TonelliShanks, but
+// with a provided quadratic non-residue z. This allows the algorithm to
+// be used in fields where the non-residues can't be found by counting
+// from two, e.g extension fields of even degree.
+func TonelliShanksWith[E any](f Field[E], i, z E) (E, error) {
+	var zeroE = f.FromInt64(0)
+
+	i = f.Canonicalize(i)
+	if f.Equal(i, zeroE) {
+		return zeroE, nil
+	}
+
+	if f.Legendre(i) != 1 {
+		return zeroE, fmt.Errorf("%v is not a square mod %s",
+			i, f.Modulus())
+	}
+
+	return tonelliShanks(f, i, z), nil
+}
+
+// tonelliShanks computes a square root of i, which must be a non-zero
+// square, given the quadratic non-residue z.
+func tonelliShanks[E any](f Field[E], i, z E) E {
+	var oneE = f.FromInt64(1)
+
+	// Write p - 1 as q * 2^s with q odd
+	var q = f.Modulus()
+	var s int
+
+	q.Sub(q, one)
+	for q.Bit(0) == 0 {
+		q.Rsh(q, 1)
+		s++
+	}
+
+	var m = s
+	var c = f.Exponentiate(z, f.FromInt(q))
+	var t = f.Exponentiate(i, f.FromInt(q))
+	q.Add(q, one)
+	q.Rsh(q, 1)
+	var r = f.Exponentiate(i, f.FromInt(q))
+
+	// Invariants: r^2 = i * t, t^(2^(m-1)) = 1 and c^(2^(m-1)) = -1.
+	for !f.Equal(t, oneE) {
+		// Find the least j, 0 < j < m such that t^(2^j) = 1
+		var j int
+		var tt = t
+
+		for !f.Equal(tt, oneE) {
+			tt = f.Multiply(tt, tt)
+			j++
+		}
+
+		// b = c^(2^(m-j-1))
+		var b = c
+		for k := 0; k < m-j-1; k++ {
+			b = f.Multiply(b, b)
+		}
+
+		m = j
+		c = f.Multiply(b, b)
+		t = f.Multiply(t, c)
+		r = f.Multiply(r, b)
+	}
+
+	return r
+}
+
+// Cipolla computes a square root of i in any prime field using
+// Cipolla's algorithm. It can be used as an alternative to
+// TonelliShanks, and is faster when p - 1 is divisible by a large power
+// of two.
+// The sqrt are +/- the returned value.
+// If i is not a square, an error is returned.
+// See https://en.wikipedia.org/wiki/Cipolla%27s_algorithm for reference.
+func Cipolla[E any](f Field[E], i E) (E, error) {
+	var zeroE = f.FromInt64(0)
+	var oneE = f.FromInt64(1)
+
+	i = f.Canonicalize(i)
+	if f.Equal(i, zeroE) || isF2(f) {
+		// In F_2 every element is its own square root
+		return i, nil
+	}
+
+	if f.Legendre(i) != 1 {
+		return zeroE, fmt.Errorf("%v is not a square mod %s",
+			i, f.Modulus())
+	}
+
+	// Find a such that w = a^2 - i is not a square.
+	var a = zeroE
+	var w E
+	for {
+		a = f.Add(a, oneE)
+		w = f.Add(f.Multiply(a, a), f.Neg(i))
+
+		if f.Legendre(w) == -1 {
+			break
+		}
+	}
+
+	// Compute (a + sqrt(w))^((p+1)/2) in F_p(sqrt(w)), where elements
+	// are represented as x + y*sqrt(w).
+	var mul = func(x1, y1, x2, y2 E) (E, E) {
+		var x = f.Add(f.Multiply(x1, x2),
+			f.Multiply(w, f.Multiply(y1, y2)))
+		var y = f.Add(f.Multiply(x1, y2), f.Multiply(y1, x2))
+
+		return x, y
+	}
+	var e = f.Modulus()
+	e.Add(e, one)
+	e.Rsh(e, 1)
+
+	var rx, ry = oneE, zeroE
+	var bx, by = a, oneE
+	for b := 0; b < e.BitLen(); b++ {
+		if e.Bit(b) != 0 {
+			rx, ry = mul(rx, ry, bx, by)
+		}
+
+		bx, by = mul(bx, by, bx, by)
+	}
+
+	// The sqrt(w) component is always zero for the result.
+	if !f.Equal(ry, zeroE) {
+		return zeroE, fmt.Errorf("%v is not a square mod %s",
+			i, f.Modulus())
+	}
+
+	return rx, nil
+}
@@ -0,0 +1,43 @@
+package math
+
+// Jacobi computes the Jacobi symbol (a/n) for an odd positive n.
+// The result is 0 if gcd(a, n) != 1, otherwise -1 or 1.
+// When n is a prime this is the Legendre symbol, i.e 1 if a is a
+// quadratic residue mod n and -1 if it is not.
+// See https://en.wikipedia.org/wiki/Jacobi_symbol for reference.
+func Jacobi(a, n int64) int {
+	var r = 1
+
+	if n <= 0 || n%2 == 0 {
+		panic(n)
+	}
+
+	a %= n
+	if a < 0 {
+		a += n
+	}
+
+	for a != 0 {
+		// Pull out factors of two using (2/n) = -1 iff n = 3, 5 mod 8
+		for a%2 == 0 {
+			a /= 2
+			if m := n % 8; m == 3 || m == 5 {
+				r = -r
+			}
+		}
+
+		// Quadratic reciprocity
+		a, n = n, a
+		if a%4 == 3 && n%4 == 3 {
+			r = -r
+		}
+
+		a %= n
+	}
+
+	if n == 1 {
+		return r
+	}
+
+	return 0
+}
@@ -0,0 +1,42 @@
+package math
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJacobi(t *testing.T) {
+	var tests = []struct {
+		a, n int64
+		r    int
+	}{
+		{1, 1, 1},
+		{0, 3, 0},
+		{2, 7, 1},
+		{3, 7, -1},
+		{-1, 7, -1},
+		{-1, 13, 1},
+		{30, 59, -1},
+		{19, 45, 1},
+		{8, 21, -1},
+		{5, 21, 1},
+		{6, 15, 0},
+		{1001, 9907, -1},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.r, Jacobi(tc.a, tc.n),
+			"Jacobi(%d, %d)", tc.a, tc.n)
+	}
+
+	// Compare with math/big for a range of odd n
+	for n := int64(1); n < 200; n += 2 {
+		for a := int64(-10); a < 2*n; a++ {
+			var exp = big.Jacobi(big.NewInt(a), big.NewInt(n))
+
+			assert.Equal(t, exp, Jacobi(a, n), "Jacobi(%d, %d)", a, n)
+		}
+	}
+}
@@ -1,5 +1,10 @@
 package math
 
+import (
+	"math/big"
+	"sort"
+)
+
 // PrimeFactors returns an ordered list of the prime factors using
 // a very naive algorithm.
 func PrimeFactors(n int64) []int64 {
@@ -27,3 +32,113 @@ func PrimeFactors(n int64) []int64 {
 
 	return pfs
 }
+
+// trialBound is the bound for trial division in PrimeFactorsBig,
+// larger factors are found with Pollard's rho.
+const trialBound = 1 << 12
+
+// rhoBatch is the number of steps of Pollard's rho between each gcd.
+const rhoBatch = 128
+
+// PrimeFactorsBig returns an ordered list of the prime factors of n.
+// Small factors are found by trial division, and the remaining ones
+// with Brent's variant of Pollard's rho and a probabilistic primality
+// test. The running time grows with the fourth root of the second
+// largest prime factor, so e.g a prime order with a small cofactor is
+// factored quickly.
+func PrimeFactorsBig(n *big.Int) []*big.Int {
+	var pfs []*big.Int
+	var q, r, sq big.Int
+
+	n = new(big.Int).Set(n)
+
+	// The easy one first
+	for n.Sign() > 0 && n.Bit(0) == 0 {
+		pfs = append(pfs, big.NewInt(2))
+		n.Rsh(n, 1)
+	}
+
+	// n is odd, so skip even numbers
+	for i := big.NewInt(3); i.Int64() < trialBound &&
+		sq.Mul(i, i).Cmp(n) <= 0; i.Add(i, big.NewInt(2)) {
+		// prime factors can be repeated
+		for q.QuoRem(n, i, &r); r.Sign() == 0; q.QuoRem(n, i, &r) {
+			pfs = append(pfs, new(big.Int).Set(i))
+			n.Set(&q)
+		}
+	}
+
+	pfs = append(pfs, factorRho(n)...)
+	sort.Slice(pfs, func(i, j int) bool {
+		return pfs[i].Cmp(pfs[j]) < 0
+	})
+
+	return pfs
+}
+
+// factorRho returns the prime factors of n, in no particular order.
+func factorRho(n *big.Int) []*big.Int {
+	if n.Cmp(big.NewInt(1)) <= 0 {
+		return nil
+	}
+
+	if n.ProbablyPrime(32) {
+		return []*big.Int{n}
+	}
+
+	// Different polynomials give different walks, one of them will
+	// find a factor
+	for c := int64(1); ; c++ {
+		var d = brent(n, big.NewInt(c))
+		if d == nil {
+			continue
+		}
+
+		var m = new(big.Int).Quo(n, d)
+
+		return append(factorRho(d), factorRho(m)...)
+	}
+}
+
+// brent returns a non-trivial factor of the composite n, using Brent's
+// variant of Pollard's rho with the polynomial x^2 + c. The gcd is only
+// computed every rhoBatch steps, on the product of the differences.
+// nil is returned if the walk fails to separate the factors.
+func brent(n, c *big.Int) *big.Int {
+	var one = big.NewInt(1)
+	var y, x, ys = big.NewInt(2), new(big.Int), new(big.Int)
+	var q, g, t = big.NewInt(1), big.NewInt(1), new(big.Int)
+	var f = func(z *big.Int) {
+		z.Mul(z, z).Add(z, c).Mod(z, n)
+	}
+
+	for r := 1; g.Cmp(one) == 0; r *= 2 {
+		x.Set(y)
+		for i := 0; i < r; i++ {
+			f(y)
+		}
+
+		for k := 0; k < r && g.Cmp(one) == 0; k += rhoBatch {
+			ys.Set(y)
+			for i := 0; i < rhoBatch && i < r-k; i++ {
+				f(y)
+				q.Mul(q, t.Sub(x, y).Abs(t)).Mod(q, n)
+			}
+			g.GCD(nil, nil, q, n)
+		}
+	}
+
+	if g.Cmp(n) == 0 {
+		// The batch overshot, redo it one step at the time
+		for g.Set(one); g.Cmp(one) == 0; {
+			f(ys)
+			g.GCD(nil, nil, t.Sub(x, ys).Abs(t), n)
+		}
+	}
+
+	if g.Cmp(n) == 0 || g.Cmp(one) == 0 {
+		return nil
+	}
+
+	return g
+}
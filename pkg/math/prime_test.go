@@ -0,0 +1,75 @@
+package math
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimeFactors(t *testing.T) {
+	var tests = []struct {
+		n   int64
+		pfs []int64
+	}{
+		{n: 2, pfs: []int64{2}},
+		{n: 12, pfs: []int64{2, 2, 3}},
+		{n: 270, pfs: []int64{2, 3, 3, 3, 5}},
+		{n: 33480829, pfs: []int64{33480829}},
+		{n: 1001, pfs: []int64{7, 11, 13}},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.pfs, PrimeFactors(tc.n))
+
+		var got []int64
+		for _, pf := range PrimeFactorsBig(big.NewInt(tc.n)) {
+			got = append(got, pf.Int64())
+		}
+
+		assert.Equal(t, tc.pfs, got)
+	}
+
+	// The factors are distinct values, even when repeated
+	var pfs = PrimeFactorsBig(big.NewInt(8))
+	pfs[0].SetInt64(5)
+	assert.Equal(t, int64(2), pfs[1].Int64())
+}
+
+func TestPrimeFactorsBigRho(t *testing.T) {
+	var p256, _ = new(big.Int).SetString("ffffffff00000000ffffffffffffffff"+
+		"bce6faada7179e84f3b9cac2fc632551", 16)
+	var p = big.NewInt(1000003)
+	var tests = []struct {
+		n   *big.Int
+		pfs []*big.Int
+	}{
+		// 2^64 + 1
+		{
+			n: new(big.Int).SetBit(big.NewInt(1), 64, 1),
+			pfs: []*big.Int{big.NewInt(274177),
+				big.NewInt(67280421310721)},
+		},
+		// Squares of primes larger than the trial division bound
+		{
+			n:   new(big.Int).Mul(p, p),
+			pfs: []*big.Int{p, p},
+		},
+		// The order of P-256 with a cofactor
+		{
+			n:   new(big.Int).Mul(p256, big.NewInt(12)),
+			pfs: []*big.Int{big.NewInt(2), big.NewInt(2),
+				big.NewInt(3), p256},
+		},
+		{
+			n: new(big.Int).Mul(big.NewInt(1000000007),
+				big.NewInt(998244353)),
+			pfs: []*big.Int{big.NewInt(998244353),
+				big.NewInt(1000000007)},
+		},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.pfs, PrimeFactorsBig(tc.n), tc.n.String())
+	}
+}
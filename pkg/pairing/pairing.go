@@ -0,0 +1,310 @@
+// Package pairing implements the Weil and Tate pairings on elliptic
+// curves over prime fields, using Miller's algorithm.
+// A pairing maps two points of order N to an N:th root of unity in the
+// extension field F_p^k, where k is the embedding degree of the curve.
+// It's bilinear, e(aP, bQ) = e(P, Q)^ab, which is what the MOV attack
+// and BLS signatures are built on.
+package pairing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kommendorkapten/sigsim/pkg/ec"
+	"github.com/kommendorkapten/sigsim/pkg/extfield"
+)
+
+// MaxEmbeddingDegree is the largest embedding degree New accepts. The
+// extension field arithmetic is done on polynomials of degree k, so
+// large degrees are slow.
+var MaxEmbeddingDegree = 12
+
+// ErrDegenerate is returned if a line function in Miller's algorithm
+// vanishes at the evaluation point, i.e the points are linearly
+// dependent.
+var ErrDegenerate = errors.New("degenerate pairing, points are dependent")
+
+// Point is a point on the curve over the extension field.
+type Point = ec.GenericPoint[extfield.Element]
+
+// Pairing holds what is needed to compute pairings on a curve.
+type Pairing struct {
+	C   *ec.Curve                          // Curve over F_p
+	F   *extfield.Field                    // F_p^k
+	K   int                                // Embedding degree
+	Ext *ec.GenericCurve[extfield.Element] // The curve over F_p^k
+
+	n   *big.Int // The order N
+	exp *big.Int // Final exponent, (p^k - 1) / N
+}
+
+// New returns the pairings for the subgroup of order N on the curve.
+// An error is returned if the embedding degree is larger than
+// MaxEmbeddingDegree.
+func New(c *ec.Curve) (*Pairing, error) {
+	var k = c.EmbeddingDegree(MaxEmbeddingDegree)
+	if k == 0 {
+		return nil, fmt.Errorf("embedding degree is larger than %d",
+			MaxEmbeddingDegree)
+	}
+
+	var f, err = extfield.New(c.F, extfield.FindIrreducible(c.F, k))
+	if err != nil {
+		return nil, err
+	}
+
+	var e = Pairing{
+		C: c,
+		F: f,
+		K: k,
+		n: big.NewInt(c.N),
+	}
+
+	if e.Ext, err = ec.NewGenericCurve[extfield.Element](f,
+		f.FromInt64(c.A), f.FromInt64(c.B)); err != nil {
+		return nil, err
+	}
+	// The fixed-base table is for G, which is not set
+	e.Ext.Strategy = ec.StrategyWNAF
+	e.Ext.N = f.FromInt(e.n)
+	e.Ext.G = e.Lift(c.G)
+
+	e.exp = f.Modulus()
+	e.exp.Sub(e.exp, big.NewInt(1))
+	e.exp.Quo(e.exp, e.n)
+
+	return &e, nil
+}
+
+// Lift maps a point over F_p to the curve over F_p^k.
+func (e *Pairing) Lift(p ec.Point) Point {
+	if p.Inf {
+		return Point{Inf: true}
+	}
+
+	return Point{X: e.F.FromInt64(p.X), Y: e.F.FromInt64(p.Y)}
+}
+
+// ScalarM calculates the scalar multiplication of a point over F_p^k.
+func (e *Pairing) ScalarM(k int64, p Point) Point {
+	if k < 0 {
+		k = -k
+		p = e.Ext.Neg(p)
+	}
+
+	return e.Ext.ScalarM(e.F.FromInt(big.NewInt(k)), p)
+}
+
+// Distort applies a distortion map to a point over F_p, mapping it to a
+// point over F_p^2 outside of E(F_p). Distortion maps exist for some
+// supersingular curves with embedding degree two:
+// y^2 = x^3 + ax with p = 3 mod 4: (x, y) -> (-x, iy), i^2 = -1.
+// y^2 = x^3 + b with p = 2 mod 3: (x, y) -> (zx, y), z^3 = 1, z != 1.
+// For other curves an error is returned.
+func (e *Pairing) Distort(p ec.Point) (Point, error) {
+	var f = e.F
+	var q = e.Lift(p)
+	var pm = e.C.F.P()
+
+	if e.K != 2 {
+		return q, errors.New("curve has no distortion map")
+	}
+
+	if p.Inf {
+		return q, nil
+	}
+
+	switch {
+	case e.C.B == 0 && pm%4 == 3:
+		var i, err = f.Sqrt(f.FromInt64(-1))
+		if err != nil {
+			return q, err
+		}
+
+		q.X = f.Neg(q.X)
+		q.Y = f.Multiply(i, q.Y)
+	case e.C.A == 0 && pm%3 == 2:
+		// z = (-1 + sqrt(-3)) / 2
+		var s, err = f.Sqrt(f.FromInt64(-3))
+		if err != nil {
+			return q, err
+		}
+
+		var inv2, _ = f.Inverse(f.FromInt64(2))
+		var z = f.Multiply(f.Add(s, f.FromInt64(-1)), inv2)
+
+		q.X = f.Multiply(z, q.X)
+	default:
+		return q, errors.New("curve has no distortion map")
+	}
+
+	return q, nil
+}
+
+// line evaluates l / v at q, where l is the line through t and s
+// (the tangent if they are equal) and v is the vertical line through
+// t + s. t + s is returned as well.
+func (e *Pairing) line(t, s, q Point) (extfield.Element, Point,
+	error) {
+	var f = e.F
+	var one = f.FromInt64(1)
+
+	if t.Inf || s.Inf {
+		return one, e.Ext.Add(t, s), nil
+	}
+
+	var l, den extfield.Element
+	var r Point
+	// s = -t, or s = t of order two
+	var vertical = f.Equal(t.X, s.X) &&
+		(!f.Equal(t.Y, s.Y) || f.Equal(t.Y, f.FromInt64(0)))
+
+	if vertical {
+		// Vertical line, t + s is the identity and v = 1
+		l = f.Add(q.X, f.Neg(t.X))
+		r = Point{Inf: true}
+		den = one
+	} else {
+		var m, inv extfield.Element
+
+		if f.Equal(t.X, s.X) {
+			m = f.Multiply(f.FromInt64(3), f.Multiply(t.X, t.X))
+			m = f.Add(m, e.Ext.A)
+			inv, _ = f.Inverse(f.Multiply(f.FromInt64(2), t.Y))
+		} else {
+			m = f.Add(s.Y, f.Neg(t.Y))
+			inv, _ = f.Inverse(f.Add(s.X, f.Neg(t.X)))
+		}
+		m = f.Multiply(m, inv)
+
+		r.X = f.Add(f.Multiply(m, m), f.Neg(f.Add(t.X, s.X)))
+		r.Y = f.Add(f.Multiply(m, f.Add(t.X, f.Neg(r.X))), f.Neg(t.Y))
+
+		// l = (y - yt) - m(x - xt), v = x - xr
+		l = f.Add(q.Y, f.Neg(t.Y))
+		l = f.Add(l, f.Neg(f.Multiply(m, f.Add(q.X, f.Neg(t.X)))))
+		den = f.Add(q.X, f.Neg(r.X))
+	}
+
+	var inv, err = f.Inverse(den)
+	if err != nil || f.Equal(l, f.FromInt64(0)) {
+		return one, r, ErrDegenerate
+	}
+
+	return f.Multiply(l, inv), r, nil
+}
+
+// Miller computes f_(n,p)(q) with Miller's algorithm, where f_(n,p) is
+// the function with divisor n(p) - ([n]p) - (n - 1)(O). If a line
+// vanishes at q, ErrDegenerate is returned.
+func (e *Pairing) Miller(n *big.Int, p, q Point) (extfield.Element,
+	error) {
+	var f = e.F
+	var r = f.FromInt64(1)
+	var t = p
+
+	for i := n.BitLen() - 2; i >= 0; i-- {
+		var g extfield.Element
+		var err error
+
+		if g, t, err = e.line(t, t, q); err != nil {
+			return r, err
+		}
+		r = f.Multiply(f.Multiply(r, r), g)
+
+		if n.Bit(i) == 1 {
+			if g, t, err = e.line(t, p, q); err != nil {
+				return r, err
+			}
+			r = f.Multiply(r, g)
+		}
+	}
+
+	return r, nil
+}
+
+// order checks that p is of order N (or the identity).
+func (e *Pairing) order(p Point) error {
+	if !e.Ext.ScalarM(e.Ext.N, p).Inf {
+		return errors.New("point is not in the subgroup of order N")
+	}
+
+	return nil
+}
+
+// Tate computes the reduced Tate pairing
+// e(p, q) = f_(N,p)(q)^((p^k - 1) / N), where p is a point of order N
+// over F_p and q a point over F_p^k. The final exponentiation maps the
+// result to an N:th root of unity, which makes it unique.
+// The pairing is degenerate if q is a multiple of p, in which case
+// ErrDegenerate is returned.
+func (e *Pairing) Tate(p ec.Point, q Point) (extfield.Element, error) {
+	var lp = e.Lift(p)
+
+	if p.Inf || q.Inf {
+		return e.F.FromInt64(1), nil
+	}
+
+	if err := e.order(lp); err != nil {
+		return e.F.FromInt64(0), err
+	}
+
+	var r, err = e.Miller(e.n, lp, q)
+	if err != nil {
+		return r, err
+	}
+
+	return e.F.Exp(r, e.exp), nil
+}
+
+// Weil computes the Weil pairing
+// e(p, q) = (-1)^N f_(N,p)(q) / f_(N,q)(p), for points p and q of order
+// N over F_p^k. The pairing is alternating, e(p, p) = 1, but Miller's
+// algorithm can't compute it when q is a multiple of p, in which case
+// ErrDegenerate is returned.
+func (e *Pairing) Weil(p, q Point) (extfield.Element, error) {
+	var f = e.F
+
+	if p.Inf || q.Inf {
+		return f.FromInt64(1), nil
+	}
+
+	for _, x := range []Point{p, q} {
+		if err := e.order(x); err != nil {
+			return f.FromInt64(0), err
+		}
+	}
+
+	var fp, err = e.Miller(e.n, p, q)
+	if err != nil {
+		return fp, err
+	}
+
+	var fq extfield.Element
+	if fq, err = e.Miller(e.n, q, p); err != nil {
+		return fq, err
+	}
+
+	var inv, _ = f.Inverse(fq)
+	var r = f.Multiply(fp, inv)
+
+	if e.n.Bit(0) == 1 {
+		r = f.Neg(r)
+	}
+
+	return r, nil
+}
+
+// Pair computes the modified Tate pairing e(p, d(q)) where d is the
+// distortion map, see Distort. Unlike the plain pairings it's
+// non-degenerate for p = q, which makes it usable with a single group
+// generated by G, as in BLS signatures.
+func (e *Pairing) Pair(p, q ec.Point) (extfield.Element, error) {
+	var dq, err = e.Distort(q)
+	if err != nil {
+		return e.F.FromInt64(0), err
+	}
+
+	return e.Tate(p, dq)
+}
@@ -0,0 +1,153 @@
+package pairing
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/kommendorkapten/sigsim/pkg/ec"
+	"github.com/kommendorkapten/sigsim/pkg/extfield"
+	"github.com/stretchr/testify/assert"
+)
+
+func random(n int64) int64 {
+	var k, _ = rand.Int(rand.Reader, big.NewInt(n-1))
+
+	return k.Int64() + 1
+}
+
+func TestNew(t *testing.T) {
+	var e, err = New(ec.DemoSupersingular)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, e.K)
+	assert.True(t, e.Ext.Valid(e.Ext.G))
+
+	// Ordinary curves have a huge embedding degree
+	_, err = New(ec.DemoCurve25)
+	assert.NotNil(t, err)
+}
+
+func TestDistort(t *testing.T) {
+	var e, _ = New(ec.DemoSupersingular)
+	var c = e.C
+
+	for i := 0; i < 10; i++ {
+		var p = c.ScalarM(random(c.N), c.G)
+		var q, err = e.Distort(p)
+
+		assert.Nil(t, err)
+		assert.True(t, e.Ext.Valid(q))
+		// The distortion map is a group homomorphism
+		var d2, _ = e.Distort(c.Add(p, p))
+		assert.True(t, e.Ext.Equal(d2, e.Ext.Add(q, q)))
+	}
+}
+
+func TestTateBilinear(t *testing.T) {
+	var e, _ = New(ec.DemoSupersingular)
+	var c = e.C
+	var one = e.F.FromInt64(1)
+	var g = c.G
+	var base, err = e.Pair(g, g)
+
+	assert.Nil(t, err)
+	// Non-degenerate, and an N:th root of unity
+	assert.False(t, e.F.Equal(one, base))
+	assert.True(t, e.F.Equal(one, e.F.Exp(base, big.NewInt(c.N))))
+
+	for i := 0; i < 5; i++ {
+		var a, b = random(c.N), random(c.N)
+		var r, err = e.Pair(c.ScalarM(a, g), c.ScalarM(b, g))
+		var ab = new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+
+		assert.Nil(t, err)
+		assert.True(t, e.F.Equal(e.F.Exp(base, ab), r))
+	}
+
+	// The plain Tate pairing is degenerate on E(F_p) x E(F_p)
+	_, err = e.Tate(g, e.Lift(c.ScalarM(2, g)))
+	assert.ErrorIs(t, err, ErrDegenerate)
+}
+
+func TestWeil(t *testing.T) {
+	var e, _ = New(ec.DemoSupersingular)
+	var c = e.C
+	var f = e.F
+	var one = f.FromInt64(1)
+	var p = e.Lift(c.G)
+	var q, _ = e.Distort(c.ScalarM(random(c.N), c.G))
+	var base, err = e.Weil(p, q)
+
+	assert.Nil(t, err)
+	assert.False(t, f.Equal(one, base))
+	assert.True(t, f.Equal(one, f.Exp(base, big.NewInt(c.N))))
+
+	// Alternating, e(q, p) = e(p, q)^-1
+	var qp, _ = e.Weil(q, p)
+	assert.True(t, f.Equal(one, f.Multiply(base, qp)))
+
+	for i := 0; i < 5; i++ {
+		var a, b = random(c.N), random(c.N)
+		var r, err = e.Weil(e.ScalarM(a, p), e.ScalarM(b, q))
+		var ab = new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+
+		assert.Nil(t, err)
+		assert.True(t, f.Equal(f.Exp(base, ab), r))
+	}
+
+	// Points of another order are rejected, (0, 0) is of order two
+	_, err = e.Weil(e.Lift(ec.Point{X: 0, Y: 0}), q)
+	assert.NotNil(t, err)
+}
+
+// TestBLS signs and verifies with the BLS signature scheme. The
+// signature on m is s = x * H(m), and it's verified by
+// e(s, G) = e(H(m), xG).
+func TestBLS(t *testing.T) {
+	var e, _ = New(ec.DemoSupersingular)
+	var c = e.C
+	var x = random(c.N)
+	var pub = c.ScalarM(x, c.G)
+	// A toy hash to the curve, the discrete logarithm of H(m) must be
+	// unknown in a real system
+	var h = func(m int64) ec.Point {
+		return c.ScalarM(m*m+17, c.G)
+	}
+	var verify = func(m int64, s ec.Point) bool {
+		var lhs, _ = e.Pair(s, c.G)
+		var rhs, _ = e.Pair(h(m), pub)
+
+		return e.F.Equal(lhs, rhs)
+	}
+	var sig = c.ScalarM(x, h(42))
+
+	assert.True(t, verify(42, sig))
+	assert.False(t, verify(43, sig))
+	assert.False(t, verify(42, c.Add(sig, c.G)))
+}
+
+// TestMOV solves a discrete logarithm on the curve by moving it to
+// F_p^2, where it's solved by brute force.
+func TestMOV(t *testing.T) {
+	var e, _ = New(ec.DemoSupersingular)
+	var c = e.C
+	var f = e.F
+	// A small logarithm keeps the search short
+	var d = int64(1234)
+	var g = c.G
+	var p = c.ScalarM(d, g)
+	var base, _ = e.Pair(g, g)
+	var target, _ = e.Pair(g, p)
+	var acc extfield.Element = f.FromInt64(1)
+	var k int64
+
+	for k = 0; k < 10000; k++ {
+		if f.Equal(acc, target) {
+			break
+		}
+		acc = f.Multiply(acc, base)
+	}
+
+	assert.Equal(t, d, k)
+}
@@ -0,0 +1,317 @@
+// Package poly implements polynomials with coefficients in a finite
+// field, i.e the ring F[x].
+package poly
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+)
+
+// Poly is a polynomial with coefficients in a finite field.
+// Coefficients are stored in increasing order, i.e c[i] is the
+// coefficient of x^i, and the leading coefficient is never zero.
+// The zero polynomial has no coefficients.
+// A Poly is never modified, all operations return a new polynomial.
+type Poly[E any] struct {
+	f field.Field[E]
+	c []E
+}
+
+// NotInvertibleError is returned when a polynomial is not invertible
+// modulo another polynomial. GCD holds the non-trivial common factor.
+type NotInvertibleError[E any] struct {
+	GCD Poly[E]
+}
+
+func (e *NotInvertibleError[E]) Error() string {
+	return fmt.Sprintf("polynomial is not invertible, common factor %s",
+		e.GCD)
+}
+
+// New returns the polynomial c[0] + c[1]x + c[2]x^2 ...
+func New[E any](f field.Field[E], c ...E) Poly[E] {
+	var cc = make([]E, len(c))
+
+	for i := range c {
+		cc[i] = f.Canonicalize(c[i])
+	}
+
+	return normalize(f, cc)
+}
+
+// Zero returns the zero polynomial.
+func Zero[E any](f field.Field[E]) Poly[E] {
+	return Poly[E]{f: f}
+}
+
+// One returns the constant polynomial 1.
+func One[E any](f field.Field[E]) Poly[E] {
+	return Poly[E]{f: f, c: []E{f.FromInt64(1)}}
+}
+
+// X returns the polynomial x.
+func X[E any](f field.Field[E]) Poly[E] {
+	return Poly[E]{f: f, c: []E{f.FromInt64(0), f.FromInt64(1)}}
+}
+
+// normalize strips any leading zero coefficients. c is used as is.
+func normalize[E any](f field.Field[E], c []E) Poly[E] {
+	var zero = f.FromInt64(0)
+	var n = len(c)
+
+	for n > 0 && f.Equal(c[n-1], zero) {
+		n--
+	}
+
+	return Poly[E]{f: f, c: c[:n]}
+}
+
+// Field returns the field of the coefficients.
+func (p Poly[E]) Field() field.Field[E] {
+	return p.f
+}
+
+// Degree returns the degree of the polynomial. The degree of the zero
+// polynomial is -1.
+func (p Poly[E]) Degree() int {
+	return len(p.c) - 1
+}
+
+// Coeff returns the coefficient of x^i.
+func (p Poly[E]) Coeff(i int) E {
+	if i < 0 || i >= len(p.c) {
+		return p.f.FromInt64(0)
+	}
+
+	return p.c[i]
+}
+
+// Lead returns the leading coefficient.
+func (p Poly[E]) Lead() E {
+	return p.Coeff(p.Degree())
+}
+
+// IsZero returns true if p is the zero polynomial.
+func (p Poly[E]) IsZero() bool {
+	return len(p.c) == 0
+}
+
+// Equal returns true if p and q are the same polynomial.
+func (p Poly[E]) Equal(q Poly[E]) bool {
+	if len(p.c) != len(q.c) {
+		return false
+	}
+
+	for i := range p.c {
+		if !p.f.Equal(p.c[i], q.c[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Add returns p + q.
+func (p Poly[E]) Add(q Poly[E]) Poly[E] {
+	var n = max(len(p.c), len(q.c))
+	var c = make([]E, n)
+
+	for i := 0; i < n; i++ {
+		c[i] = p.f.Add(p.Coeff(i), q.Coeff(i))
+	}
+
+	return normalize(p.f, c)
+}
+
+// Neg returns -p.
+func (p Poly[E]) Neg() Poly[E] {
+	var c = make([]E, len(p.c))
+
+	for i := range p.c {
+		c[i] = p.f.Neg(p.c[i])
+	}
+
+	return Poly[E]{f: p.f, c: c}
+}
+
+// Sub returns p - q.
+func (p Poly[E]) Sub(q Poly[E]) Poly[E] {
+	return p.Add(q.Neg())
+}
+
+// Scale returns k * p.
+func (p Poly[E]) Scale(k E) Poly[E] {
+	var c = make([]E, len(p.c))
+
+	for i := range p.c {
+		c[i] = p.f.Multiply(k, p.c[i])
+	}
+
+	return normalize(p.f, c)
+}
+
+// Mul returns p * q.
+func (p Poly[E]) Mul(q Poly[E]) Poly[E] {
+	if p.IsZero() || q.IsZero() {
+		return Zero(p.f)
+	}
+
+	var c = make([]E, len(p.c)+len(q.c)-1)
+	var zero = p.f.FromInt64(0)
+
+	for i := range c {
+		c[i] = zero
+	}
+
+	for i := range p.c {
+		for j := range q.c {
+			c[i+j] = p.f.Add(c[i+j], p.f.Multiply(p.c[i], q.c[j]))
+		}
+	}
+
+	return normalize(p.f, c)
+}
+
+// DivMod returns the quotient and remainder of p / q, i.e p = a*q + r
+// where the degree of r is less than the degree of q.
+// DivMod panics if q is the zero polynomial.
+func (p Poly[E]) DivMod(q Poly[E]) (Poly[E], Poly[E]) {
+	if q.IsZero() {
+		panic("division by zero polynomial")
+	}
+
+	if p.Degree() < q.Degree() {
+		return Zero(p.f), p
+	}
+
+	var inv, err = p.f.Inverse(q.Lead())
+	if err != nil {
+		panic(err)
+	}
+
+	var r = make([]E, len(p.c))
+	var a = make([]E, len(p.c)-len(q.c)+1)
+	var dq = q.Degree()
+
+	copy(r, p.c)
+	for i := len(a) - 1; i >= 0; i-- {
+		// Eliminate the coefficient for x^(i+dq)
+		var k = p.f.Multiply(r[i+dq], inv)
+
+		a[i] = k
+		for j := range q.c {
+			r[i+j] = p.f.Add(r[i+j], p.f.Neg(p.f.Multiply(k, q.c[j])))
+		}
+	}
+
+	return normalize(p.f, a), normalize(p.f, r[:dq])
+}
+
+// Mod returns p mod q.
+func (p Poly[E]) Mod(q Poly[E]) Poly[E] {
+	var _, r = p.DivMod(q)
+
+	return r
+}
+
+// MulMod returns p * q mod m.
+func (p Poly[E]) MulMod(q, m Poly[E]) Poly[E] {
+	return p.Mul(q).Mod(m)
+}
+
+// PowMod returns p^e mod m, e must not be negative.
+func (p Poly[E]) PowMod(e *big.Int, m Poly[E]) Poly[E] {
+	var r = One(p.f).Mod(m)
+	var b = p.Mod(m)
+
+	// Left to right square and multiply
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		r = r.MulMod(r, m)
+		if e.Bit(i) != 0 {
+			r = r.MulMod(b, m)
+		}
+	}
+
+	return r
+}
+
+// Monic returns p scaled so the leading coefficient is one.
+func (p Poly[E]) Monic() Poly[E] {
+	if p.IsZero() {
+		return p
+	}
+
+	var inv, err = p.f.Inverse(p.Lead())
+	if err != nil {
+		panic(err)
+	}
+
+	return p.Scale(inv)
+}
+
+// GCD returns the monic greatest common divisor of p and q.
+func GCD[E any](p, q Poly[E]) Poly[E] {
+	for !q.IsZero() {
+		p, q = q, p.Mod(q)
+	}
+
+	return p.Monic()
+}
+
+// InverseMod returns the inverse of p modulo m. If p and m are not
+// coprime, a *NotInvertibleError holding their greatest common divisor
+// is returned.
+func (p Poly[E]) InverseMod(m Poly[E]) (Poly[E], error) {
+	// Extended Euclidean algorithm, only tracking the coefficient for p
+	var t, newT = Zero(p.f), One(p.f)
+	var r, newR = m, p.Mod(m)
+
+	for !newR.IsZero() {
+		var q, rem = r.DivMod(newR)
+
+		t, newT = newT, t.Sub(q.Mul(newT))
+		r, newR = newR, rem
+	}
+
+	if r.Degree() != 0 {
+		return Zero(p.f), &NotInvertibleError[E]{GCD: r.Monic()}
+	}
+
+	// r is a constant, scale t with its inverse
+	var inv, err = p.f.Inverse(r.Lead())
+	if err != nil {
+		panic(err)
+	}
+
+	return t.Scale(inv).Mod(m), nil
+}
+
+func (p Poly[E]) String() string {
+	var terms []string
+
+	if p.IsZero() {
+		return "0"
+	}
+
+	for i := len(p.c) - 1; i >= 0; i-- {
+		var c = p.f.Int(p.c[i])
+
+		if c.Sign() == 0 {
+			continue
+		}
+
+		switch i {
+		case 0:
+			terms = append(terms, c.String())
+		case 1:
+			terms = append(terms, c.String()+"x")
+		default:
+			terms = append(terms, fmt.Sprintf("%sx^%d", c, i))
+		}
+	}
+
+	return strings.Join(terms, " + ")
+}
@@ -0,0 +1,116 @@
+package poly
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/kommendorkapten/sigsim/pkg/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArithmetic(t *testing.T) {
+	var f = field.NewFinite(7)
+	// x^2 + 1 and x + 3
+	var p = New[int64](f, 1, 0, 1)
+	var q = New[int64](f, 3, 1)
+
+	assert.Equal(t, 2, p.Degree())
+	assert.True(t, New[int64](f, 0, 0, 0).IsZero())
+	assert.Equal(t, -1, Zero[int64](f).Degree())
+	assert.True(t, p.Add(q).Equal(New[int64](f, 4, 1, 1)))
+	assert.True(t, p.Sub(p).IsZero())
+	// (x^2 + 1)(x + 3) = x^3 + 3x^2 + x + 3
+	assert.True(t, p.Mul(q).Equal(New[int64](f, 3, 1, 3, 1)))
+	assert.Equal(t, "1x^2 + 1", p.String())
+
+	// x^2 + 1 = (x + 4)(x + 3) + 3
+	var a, r = p.DivMod(q)
+	assert.True(t, a.Equal(New[int64](f, 4, 1)))
+	assert.True(t, r.Equal(New[int64](f, 3)))
+	assert.True(t, a.Mul(q).Add(r).Equal(p))
+}
+
+func TestPowMod(t *testing.T) {
+	var f = field.NewFinite(13)
+	var m = New[int64](f, 2, 0, 5, 1)
+	var x = X[int64](f)
+	var exp = One[int64](f)
+
+	for i := int64(0); i < 50; i++ {
+		assert.True(t, exp.Equal(x.PowMod(big.NewInt(i), m)),
+			"x^%d", i)
+		exp = exp.MulMod(x, m)
+	}
+}
+
+func TestInverseMod(t *testing.T) {
+	var f = field.NewBigFinite(big.NewInt(101))
+	var k = func(i int64) *big.Int {
+		return big.NewInt(i)
+	}
+	// x^3 + 2x + 6 is irreducible mod 101
+	var m = New(f, k(6), k(2), k(0), k(1))
+	var p = New(f, k(5), k(100), k(3))
+
+	var inv, err = p.InverseMod(m)
+	assert.Nil(t, err)
+	assert.True(t, p.MulMod(inv, m).Equal(One(f)))
+
+	// (x + 1)(x + 2) and (x + 1)(x + 5) share the factor x + 1
+	var g = New(f, k(1), k(1))
+	m = g.Mul(New(f, k(2), k(1)))
+	p = g.Mul(New(f, k(5), k(1)))
+
+	_, err = p.InverseMod(m)
+	var nie *NotInvertibleError[*big.Int]
+	assert.True(t, errors.As(err, &nie))
+	assert.True(t, g.Equal(nie.GCD))
+	assert.True(t, g.Equal(GCD(m, p)))
+}
+
+func TestRoots(t *testing.T) {
+	var f = field.NewFinite(1009)
+	var tests = []struct {
+		roots []int64
+		extra Poly[int64]
+	}{
+		{roots: []int64{}, extra: New[int64](f, 11, 0, 1)},
+		{roots: []int64{5}, extra: One[int64](f)},
+		{roots: []int64{0, 1, 2, 3, 500, 1008}, extra: One[int64](f)},
+		{roots: []int64{7, 9, 100}, extra: New[int64](f, 11, 0, 1)},
+	}
+
+	for _, tc := range tests {
+		var p = tc.extra
+
+		for _, r := range tc.roots {
+			p = p.Mul(New[int64](f, f.Neg(r), 1))
+			assert.Equal(t, int64(0), p.Eval(r))
+		}
+		// Repeated roots are only reported once
+		p = p.Mul(p)
+
+		var got = p.Roots()
+		if len(tc.roots) == 0 {
+			assert.Empty(t, got)
+		} else {
+			assert.Equal(t, tc.roots, got)
+		}
+	}
+
+	// F_2
+	var f2 = field.NewFinite(2)
+	assert.Equal(t, []int64{1}, New[int64](f2, 1, 0, 1).Roots())
+}
+
+func TestEval(t *testing.T) {
+	var f = field.NewFinite(101)
+	// 3x^3 + 2x + 5
+	var p = New[int64](f, 5, 2, 0, 3)
+
+	for x := int64(0); x < 101; x++ {
+		assert.Equal(t, (3*x*x*x+2*x+5)%101, p.Eval(x))
+		assert.Equal(t, (9*x*x+2)%101, p.Derivative().Eval(x))
+	}
+}
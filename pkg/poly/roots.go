@@ -0,0 +1,108 @@
+package poly
+
+import (
+	"crypto/rand"
+	"math/big"
+	"sort"
+)
+
+// Eval evaluates the polynomial at x using Horner's method.
+func (p Poly[E]) Eval(x E) E {
+	var r = p.f.FromInt64(0)
+
+	for i := len(p.c) - 1; i >= 0; i-- {
+		r = p.f.Add(p.f.Multiply(r, x), p.c[i])
+	}
+
+	return r
+}
+
+// Derivative returns the formal derivative of p.
+func (p Poly[E]) Derivative() Poly[E] {
+	if p.Degree() < 1 {
+		return Zero(p.f)
+	}
+
+	var c = make([]E, len(p.c)-1)
+
+	for i := range c {
+		c[i] = p.f.Multiply(p.f.FromInt64(int64(i+1)), p.c[i+1])
+	}
+
+	return normalize(p.f, c)
+}
+
+// Roots returns the distinct roots of p in the field, in increasing
+// order. The zero polynomial has every element as a root, so nil is
+// returned for it.
+// The roots are found by first computing gcd(p, x^q - x), which is the
+// product of all linear factors of p. The linear factors are separated
+// with the Cantor-Zassenhaus method: for a random d, about half of the
+// roots r has r + d as a quadratic residue, i.e they are roots of
+// (x + d)^((q-1)/2) - 1.
+func (p Poly[E]) Roots() []E {
+	var q = p.f.Modulus()
+	var x = X(p.f)
+	var roots []E
+
+	if p.Degree() < 1 {
+		return nil
+	}
+
+	if q.Cmp(big.NewInt(2)) == 0 {
+		// No odd exponent to work with, just try both elements
+		for i := int64(0); i < 2; i++ {
+			var e = p.f.FromInt64(i)
+
+			if p.f.Equal(p.Eval(e), p.f.FromInt64(0)) {
+				roots = append(roots, e)
+			}
+		}
+
+		return roots
+	}
+
+	var g = GCD(p, x.PowMod(q, p).Sub(x))
+
+	roots = splitLinear(g, roots)
+	sort.Slice(roots, func(i, j int) bool {
+		return p.f.Int(roots[i]).Cmp(p.f.Int(roots[j])) < 0
+	})
+
+	return roots
+}
+
+// splitLinear appends the roots of g to roots. g must be monic and a
+// product of distinct linear factors, and the field order must be odd.
+func splitLinear[E any](g Poly[E], roots []E) []E {
+	var f = g.f
+	var e = new(big.Int).Rsh(f.Modulus(), 1)
+
+	switch g.Degree() {
+	case 0:
+		return roots
+	case 1:
+		// x + c
+		return append(roots, f.Neg(g.Coeff(0)))
+	}
+
+	for {
+		var d, err = rand.Int(rand.Reader, f.Modulus())
+		if err != nil {
+			panic(err)
+		}
+
+		var s = New(f, f.FromInt(d), f.FromInt64(1))
+		var h = GCD(g, s.PowMod(e, g).Sub(One(f)))
+
+		if h.Degree() < 1 || h.Degree() == g.Degree() {
+			continue
+		}
+
+		var rest, _ = g.DivMod(h)
+
+		roots = splitLinear(h, roots)
+
+		return splitLinear(rest, roots)
+	}
+}